@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+
+	"github.com/chia-network/github-bot/internal/config"
+	github2 "github.com/chia-network/github-bot/internal/github"
+	"github.com/chia-network/github-bot/internal/health"
+)
+
+var assignReviewersCmd = &cobra.Command{
+	Use:   "assign-reviewers",
+	Short: "Requests reviews on community PRs without a reviewer from the internal team members who own the changed files in CODEOWNERS",
+	Run: func(cmd *cobra.Command, args []string) {
+		slogs.Init("info")
+		cfg, err := config.LoadConfig(viper.GetString("config"))
+		if err != nil {
+			slogs.Logr.Fatal("Error loading config", "error", err)
+		}
+		client, err := github2.NewClient(cfg, dryRunRecorder())
+		if err != nil {
+			slogs.Logr.Fatal("Error creating GitHub client", "error", err)
+		}
+
+		store, err := openAssignmentStore()
+		if err != nil {
+			slogs.Logr.Error("Could not initialize database connection", "error", err)
+			return
+		}
+
+		loop := viper.GetBool("loop")
+		loopDuration := viper.GetDuration("loop-time")
+		if loop {
+			health.RegisterJob("assign-reviewers", loopDuration)
+			startStatusServer(cfg, client, store)
+		}
+		ctx := context.Background()
+
+		for {
+			err = runCycle(ctx, "assign-reviewers", func(ctx context.Context) error {
+				return runAssignReviewers(ctx, client, cfg, github2.NewSnapshot(client, cfg, 0), store)
+			})
+			if err != nil {
+				slogs.Logr.Error("Error assigning reviewers", "error", err)
+				time.Sleep(loopDuration)
+				continue
+			}
+
+			if !loop {
+				break
+			}
+			slogs.Logr.Info("Waiting for next iteration", "duration", loopDuration.String())
+			time.Sleep(loopDuration)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(assignReviewersCmd)
+}
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// configCmd groups the commands for checking and inspecting the config file
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Commands for validating and inspecting the config file",
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+}
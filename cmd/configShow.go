@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+
+	"github.com/chia-network/github-bot/internal/config"
+)
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Prints the config with defaults and per-repo overrides resolved and secrets redacted",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig(viper.GetString("config"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		out, err := yaml.Marshal(cfg.Resolved().Redacted())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error encoding config: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(string(out))
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configShowCmd)
+}
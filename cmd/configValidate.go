@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/chia-network/github-bot/internal/config"
+	github2 "github.com/chia-network/github-bot/internal/github"
+)
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Checks the config file for unknown keys and invalid values, and optionally that its team, repos and labels exist on GitHub",
+	Run: func(cmd *cobra.Command, args []string) {
+		slogs.Init("info")
+		path := viper.GetString("config")
+		cfg, err := config.LoadConfig(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		if viper.GetBool("check-github") {
+			client, err := github2.NewClient(cfg, dryRunRecorder())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error creating GitHub client: %v\n", err)
+				os.Exit(1)
+			}
+			err = github2.ValidateConfig(context.Background(), client, cfg)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "config in %s does not match GitHub:\n%v\n", path, err)
+				os.Exit(1)
+			}
+		}
+
+		fmt.Printf("%s is valid\n", path)
+	},
+}
+
+func init() {
+	configValidateCmd.Flags().Bool("check-github", false, "Also check that the internal team, repos and labels exist on GitHub")
+
+	cobra.CheckErr(viper.BindPFlag("check-github", configValidateCmd.Flags().Lookup("check-github")))
+
+	configCmd.AddCommand(configValidateCmd)
+}
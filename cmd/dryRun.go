@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"sync"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/spf13/viper"
+
+	"github.com/chia-network/github-bot/internal/dryrun"
+)
+
+var (
+	recorderOnce sync.Once
+	recorder     *dryrun.Recorder
+)
+
+// dryRunRecorder returns the recorder shared by everything in the process when --dry-run is set, and nil otherwise
+func dryRunRecorder() *dryrun.Recorder {
+	if !viper.GetBool("dry-run") {
+		return nil
+	}
+	recorderOnce.Do(func() {
+		var err error
+		recorder, err = dryrun.New(viper.GetString("dry-run-plan"))
+		if err != nil {
+			slogs.Logr.Fatal("Error setting up dry run", "error", err)
+		}
+		slogs.Logr.Info("Dry run enabled, no changes will be made to GitHub, chat or the database")
+	})
+	return recorder
+}
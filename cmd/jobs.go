@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/google/go-github/v60/github"
+
+	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/database"
+	github2 "github.com/chia-network/github-bot/internal/github"
+	"github.com/chia-network/github-bot/internal/health"
+	"github.com/chia-network/github-bot/internal/label"
+	"github.com/chia-network/github-bot/internal/metrics"
+	"github.com/chia-network/github-bot/internal/notify"
+	"github.com/chia-network/github-bot/internal/templates"
+)
+
+// runCycle runs one iteration of a job with its GitHub API calls attributed to it, then logs what the iteration spent
+// and records its duration and outcome for the metrics and health checks
+func runCycle(ctx context.Context, job string, run func(ctx context.Context) error) error {
+	start := time.Now()
+	defer github2.LogAPIUsage(job)
+	err := run(github2.WithJob(ctx, job))
+	metrics.ObserveJob(job, start, err)
+	health.RecordCycle(ctx, job, err)
+	return err
+}
+
+func checkRepoNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.CheckRepos))
+	for _, repo := range cfg.CheckRepos {
+		names = append(names, repo.Name)
+	}
+	return names
+}
+
+// The functions below are a single iteration of each job, shared by the standalone commands and the run daemon
+
+// runLabelPRs also welcomes first-time contributors when welcomeStore is not nil
+func runLabelPRs(ctx context.Context, client *github.Client, cfg *config.Config, snapshot *github2.Snapshot, welcomeStore database.WelcomeStore) error {
+	slogs.Logr.Info("Labeling Pull Requests")
+	err := label.PullRequests(ctx, client, cfg, snapshot)
+	if err != nil {
+		return fmt.Errorf("error labeling pull requests: %w", err)
+	}
+	if welcomeStore != nil {
+		err = github2.WelcomeFirstTimeContributors(ctx, client, cfg, snapshot, welcomeStore)
+		if err != nil {
+			return fmt.Errorf("error welcoming first-time contributors: %w", err)
+		}
+	}
+	return nil
+}
+
+func runNotifyPendingCI(ctx context.Context, client *github.Client, cfg *config.Config, snapshot *github2.Snapshot, datastore database.Store, approvalStore database.ApprovalStore, notifier notify.Notifier) error {
+	slogs.Logr.Info("Checking for community PRs that are waiting for CI to run")
+	listPendingPRs, err := github2.CheckForPendingCI(ctx, client, cfg, snapshot, approvalStore)
+	if err != nil {
+		return fmt.Errorf("error obtaining a list of pending PRs: %w", err)
+	}
+
+	// Auto-approvals are announced as they happen rather than waiting for the digest
+	var pendingPRs []github2.PendingPR
+	for _, pr := range listPendingPRs {
+		if pr.AutoApprovedReason != "" {
+			announceAutoApproval(ctx, cfg, notifier, pr)
+			continue
+		}
+		pendingPRs = append(pendingPRs, pr)
+	}
+	listPendingPRs = pendingPRs
+
+	counts := map[string]int{}
+	for _, pr := range listPendingPRs {
+		counts[pr.Owner+"/"+pr.Repo]++
+	}
+	metrics.SetPerRepo(metrics.PendingCIPRs, checkRepoNames(cfg), counts)
+
+	if cfg.Jobs.NotifyPendingCI.Digest {
+		entries := make([]digestEntry, 0, len(listPendingPRs))
+		for _, pr := range listPendingPRs {
+			entries = append(entries, digestEntry{
+				Owner:            pr.Owner,
+				Repo:             pr.Repo,
+				PRNumber:         pr.PRNumber,
+				URL:              pr.URL,
+				Title:            pr.Title,
+				Author:           pr.Author,
+				CreatedAt:        pr.CreatedAt,
+				LastTeamActivity: pr.LastTeamActivity,
+				SensitiveFiles:   pr.SensitiveFiles,
+			})
+		}
+		title, err := digestTitle(cfg, templates.PendingCIDigestTitle, cfg.StaleAfter)
+		if err != nil {
+			return fmt.Errorf("error rendering pending CI digest title: %w", err)
+		}
+		notifyDigest(ctx, datastore, notifier, cfg, title, entries)
+		return nil
+	}
+
+	for _, pr := range listPendingPRs {
+		notifyPendingPR(ctx, cfg, datastore, notifier, pr)
+	}
+	return nil
+}
+
+func runNotifyStale(ctx context.Context, client *github.Client, cfg *config.Config, snapshot *github2.Snapshot, datastore database.Store, notifier notify.Notifier) error {
+	slogs.Logr.Info("Checking for community PRs that have no recent update from the team")
+	listStalePRs, err := github2.CheckStalePRs(ctx, client, cfg, snapshot)
+	if err != nil {
+		return fmt.Errorf("error obtaining a list of stale PRs: %w", err)
+	}
+
+	counts := map[string]int{}
+	for _, pr := range listStalePRs {
+		counts[pr.Owner+"/"+pr.Repo]++
+	}
+	metrics.SetPerRepo(metrics.StalePRs, checkRepoNames(cfg), counts)
+
+	if cfg.Jobs.NotifyStale.Digest {
+		entries := make([]digestEntry, 0, len(listStalePRs))
+		for _, pr := range listStalePRs {
+			entries = append(entries, digestEntry{
+				Owner:            pr.Owner,
+				Repo:             pr.Repo,
+				PRNumber:         pr.PRNumber,
+				URL:              pr.URL,
+				Title:            pr.Title,
+				Author:           pr.Author,
+				CreatedAt:        pr.CreatedAt,
+				LastTeamActivity: pr.LastTeamActivity,
+			})
+		}
+		title, err := digestTitle(cfg, templates.StaleDigestTitle, cfg.StaleAfter)
+		if err != nil {
+			return fmt.Errorf("error rendering stale PR digest title: %w", err)
+		}
+		notifyDigest(ctx, datastore, notifier, cfg, title, entries)
+		return nil
+	}
+
+	for _, pr := range listStalePRs {
+		policy := cfg.PolicyFor(pr.Owner + "/" + pr.Repo)
+		message, err := renderMessage(templates.StaleTitle, policy.Templates.StaleTitle, templates.StaleMessage, policy.Templates.StaleMessage, pr.TemplateData(cfg))
+		if err != nil {
+			slogs.Logr.Error("Error rendering stale PR message", "repository", pr.Repo, "PR", pr.PRNumber, "error", err)
+			continue
+		}
+		notifyPR(ctx, datastore, notifier, policy.RenotifyInterval, pr.Repo, pr.PRNumber, message)
+	}
+	return nil
+}
+
+func runNotifyStaleIssues(ctx context.Context, client *github.Client, cfg *config.Config, snapshot *github2.Snapshot, datastore database.Store, notifier notify.Notifier) error {
+	slogs.Logr.Info("Checking for community issues that have no recent update from the team")
+	listStaleIssues, err := github2.CheckStaleIssues(ctx, client, cfg, snapshot)
+	if err != nil {
+		return fmt.Errorf("error obtaining a list of stale issues: %w", err)
+	}
+
+	counts := map[string]int{}
+	for _, issue := range listStaleIssues {
+		counts[issue.Owner+"/"+issue.Repo]++
+	}
+	metrics.SetPerRepo(metrics.StaleIssues, checkRepoNames(cfg), counts)
+
+	if cfg.Jobs.NotifyStaleIssues.Digest {
+		entries := make([]digestEntry, 0, len(listStaleIssues))
+		for _, issue := range listStaleIssues {
+			entries = append(entries, digestEntry{
+				Owner:            issue.Owner,
+				Repo:             issue.Repo,
+				PRNumber:         issue.IssueNumber,
+				URL:              issue.URL,
+				Title:            issue.Title,
+				Author:           issue.Author,
+				CreatedAt:        issue.CreatedAt,
+				LastTeamActivity: issue.LastTeamActivity,
+			})
+		}
+		title, err := digestTitle(cfg, templates.StaleIssueDigestTitle, cfg.StaleIssuesAfter())
+		if err != nil {
+			return fmt.Errorf("error rendering stale issue digest title: %w", err)
+		}
+		notifyDigest(ctx, datastore, notifier, cfg, title, entries)
+		return nil
+	}
+
+	for _, issue := range listStaleIssues {
+		policy := cfg.PolicyFor(issue.Owner + "/" + issue.Repo)
+		message, err := renderMessage(templates.StaleIssueTitle, policy.Templates.StaleIssueTitle, templates.StaleIssueMessage, policy.Templates.StaleIssueMessage, issue.TemplateData(cfg))
+		if err != nil {
+			slogs.Logr.Error("Error rendering stale issue message", "repository", issue.Repo, "issue", issue.IssueNumber, "error", err)
+			continue
+		}
+		notifyPR(ctx, datastore, notifier, policy.RenotifyInterval, issue.Repo, issue.IssueNumber, message)
+	}
+	return nil
+}
+
+func runAssignReviewers(ctx context.Context, client *github.Client, cfg *config.Config, snapshot *github2.Snapshot, store database.AssignmentStore) error {
+	slogs.Logr.Info("Requesting reviews on community PRs from code owners")
+	assignments, err := github2.AssignReviewers(ctx, client, cfg, snapshot, store)
+	if err != nil {
+		return fmt.Errorf("error assigning reviewers: %w", err)
+	}
+	for _, assignment := range assignments {
+		slogs.Logr.Info("Requested reviews", "repository", assignment.Owner+"/"+assignment.Repo, "PR", assignment.PRNumber, "reviewers", assignment.Reviewers)
+	}
+	return nil
+}
+
+func runNotifyUnsigned(ctx context.Context, client *github.Client, cfg *config.Config, snapshot *github2.Snapshot) error {
+	slogs.Logr.Info("Checking for PRs that have unsigned commits")
+	listUnsignedPRs, err := github2.CheckUnsignedCommits(ctx, client, cfg, snapshot)
+	if err != nil {
+		return fmt.Errorf("error obtaining a list of PRs with unsigned commits: %w", err)
+	}
+
+	counts := map[string]int{}
+	for _, pr := range listUnsignedPRs {
+		counts[pr.Owner+"/"+pr.Repo]++
+	}
+	metrics.SetPerRepo(metrics.UnsignedPRs, checkRepoNames(cfg), counts)
+
+	for _, pr := range listUnsignedPRs {
+		err = github2.CheckAndComment(ctx, client, cfg, pr)
+		if err != nil {
+			slogs.Logr.Error("Error commenting on PR", "error", err, "repository", pr.Repo, "PR", pr.PRNumber)
+			continue
+		}
+	}
+	return nil
+}
@@ -1,15 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"time"
 
 	"github.com/chia-network/go-modules/pkg/slogs"
-	"github.com/google/go-github/v60/github"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/chia-network/github-bot/internal/config"
-	"github.com/chia-network/github-bot/internal/label"
+	"github.com/chia-network/github-bot/internal/database"
+	github2 "github.com/chia-network/github-bot/internal/github"
+	"github.com/chia-network/github-bot/internal/health"
 )
 
 // labelPRsCmd represents the labelPRs command
@@ -22,13 +24,31 @@ var labelPRsCmd = &cobra.Command{
 		if err != nil {
 			slogs.Logr.Fatal("Error loading config", "error", err)
 		}
-		client := github.NewClient(nil).WithAuthToken(cfg.GithubToken)
+		client, err := github2.NewClient(cfg, dryRunRecorder())
+		if err != nil {
+			slogs.Logr.Fatal("Error creating GitHub client", "error", err)
+		}
+
+		welcomeStore, err := openWelcomeStore(cfg)
+		if err != nil {
+			slogs.Logr.Fatal("Could not initialize database connection", "error", err)
+		}
 
 		loop := viper.GetBool("loop")
 		loopDuration := viper.GetDuration("loop-time")
+		if loop {
+			health.RegisterJob("label-prs", loopDuration)
+			var datastores []database.Pinger
+			if welcomeStore != nil {
+				datastores = append(datastores, welcomeStore)
+			}
+			startStatusServer(cfg, client, datastores...)
+		}
+		ctx := context.Background()
 		for {
-			slogs.Logr.Info("Labeling Pull Requests")
-			err = label.PullRequests(client, cfg)
+			err = runCycle(ctx, "label-prs", func(ctx context.Context) error {
+				return runLabelPRs(ctx, client, cfg, github2.NewSnapshot(client, cfg, 0), welcomeStore)
+			})
 			if err != nil {
 				slogs.Logr.Fatal("Error labeling pull requests", "error", err)
 			}
@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/spf13/viper"
+
+	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/database"
+	"github.com/chia-network/github-bot/internal/dryrun"
+	github2 "github.com/chia-network/github-bot/internal/github"
+	"github.com/chia-network/github-bot/internal/notify"
+	"github.com/chia-network/github-bot/internal/templates"
+)
+
+// reviewAssignmentsTable holds the reviews requested by assign-reviewers
+const reviewAssignmentsTable = "review_assignments"
+
+// welcomedContributorsTable holds the authors welcomed to each repo
+const welcomedContributorsTable = "welcomed_contributors"
+
+// autoApprovalsTable holds the workflow runs approved automatically
+const autoApprovalsTable = "auto_approvals"
+
+// renderMessage renders a job's title and message templates for a single PR
+func renderMessage(titleName, titleTemplate, messageName, messageTemplate string, data templates.Data) (notify.Message, error) {
+	title, err := templates.Render(titleName, titleTemplate, data)
+	if err != nil {
+		return notify.Message{}, err
+	}
+	description, err := templates.Render(messageName, messageTemplate, data)
+	if err != nil {
+		return notify.Message{}, err
+	}
+	return notify.Message{Title: title, Description: description}, nil
+}
+
+// notifyPendingPR sends the pending CI message for a single PR, or announces that its runs were auto-approved
+func notifyPendingPR(ctx context.Context, cfg *config.Config, datastore database.Store, notifier notify.Notifier, pr github2.PendingPR) {
+	if pr.AutoApprovedReason != "" {
+		announceAutoApproval(ctx, cfg, notifier, pr)
+		return
+	}
+	policy := cfg.PolicyFor(pr.Owner + "/" + pr.Repo)
+	message, err := renderMessage(templates.PendingCITitle, policy.Templates.PendingCITitle, templates.PendingCIMessage, policy.Templates.PendingCIMessage, pr.TemplateData(cfg))
+	if err != nil {
+		slogs.Logr.Error("Error rendering pending CI message", "repository", pr.Repo, "PR", pr.PRNumber, "error", err)
+		return
+	}
+	notifyPR(ctx, datastore, notifier, policy.RenotifyInterval, pr.Repo, pr.PRNumber, message)
+}
+
+// announceAutoApproval tells the channel that a PR's workflow runs were approved without a maintainer.
+// Every approval is announced, regardless of suppress_messages or the re-notify interval.
+func announceAutoApproval(ctx context.Context, cfg *config.Config, notifier notify.Notifier, pr github2.PendingPR) {
+	policy := cfg.PolicyFor(pr.Owner + "/" + pr.Repo)
+	title, err := templates.Render(templates.AutoApprovedTitle, policy.Templates.AutoApprovedTitle, pr.TemplateData(cfg))
+	if err != nil {
+		slogs.Logr.Error("Error rendering auto-approval title", "repository", pr.Repo, "PR", pr.PRNumber, "error", err)
+		return
+	}
+	message := notify.Message{
+		Title:       title,
+		Description: fmt.Sprintf("%s by %s (%s)", pr.URL, pr.Author, pr.AutoApprovedReason),
+	}
+	if err := notifier.Notify(ctx, message); err != nil {
+		slogs.Logr.Error("Failed to announce auto-approval", "repository", pr.Repo, "PR", pr.PRNumber, "error", err)
+	}
+}
+
+// notifyPR sends a message for a PR, or an issue, unless messages are suppressed for it or one was already sent within sendMsgDuration
+func notifyPR(ctx context.Context, datastore database.Store, notifier notify.Notifier, sendMsgDuration time.Duration, repo string, prNumber int, message notify.Message) {
+	if !dueForNotification(datastore, sendMsgDuration, repo, prNumber) {
+		return
+	}
+
+	slogs.Logr.Info("Sending message for", "repository", repo, "PR", int64(prNumber))
+	if err := notifier.Notify(ctx, message); err != nil {
+		slogs.Logr.Error("Failed to send message", "error", err)
+		time.Sleep(15 * time.Second) // This is to prevent "error response: 429 Too Many Requests""
+		return
+	}
+	slogs.Logr.Info("Message sent for PR", "repository", repo, "PR", int64(prNumber))
+	recordNotification(datastore, repo, prNumber)
+}
+
+// dueForNotification reports whether a PR should be included in this cycle's messages.
+// It skips suppressed and snoozed PRs and those notified within sendMsgDuration.
+func dueForNotification(datastore database.Store, sendMsgDuration time.Duration, repo string, prNumber int) bool {
+	prInfo, err := datastore.GetPRData(repo, int64(prNumber))
+	if err != nil {
+		slogs.Logr.Error("Error checking PR info in database", "error", err)
+		return false
+	}
+
+	if prInfo != nil && prInfo.SuppressMessages {
+		slogs.Logr.Info("Skipping message for PR due to suppress_messages flag", "repository", repo, "PR", int64(prNumber))
+		return false
+	}
+	if prInfo != nil && time.Now().Before(prInfo.SnoozedUntil) {
+		slogs.Logr.Info("Skipping message for snoozed PR", "repository", repo, "PR", int64(prNumber), "until", prInfo.SnoozedUntil.Format(time.RFC3339))
+		return false
+	}
+
+	// New PRs are due, as are those whose re-notify interval has elapsed since the last message was issued
+	return prInfo == nil || time.Since(prInfo.LastMessageSent) > sendMsgDuration
+}
+
+// recordNotification stores last_message_sent for a PR once its message was delivered
+func recordNotification(datastore database.Store, repo string, prNumber int) {
+	slogs.Logr.Info("Updating last_message_sent time in db", "repository", repo, "PR", int64(prNumber))
+	if err := datastore.StorePRData(repo, int64(prNumber)); err != nil {
+		slogs.Logr.Error("Error storing PR data", "repository", repo, "PR", int64(prNumber), "error", err)
+	}
+}
+
+// digestEntry is one PR, or issue for notify-stale-issues, in a digest message
+type digestEntry struct {
+	Owner            string
+	Repo             string
+	PRNumber         int
+	URL              string
+	Title            string
+	Author           string
+	CreatedAt        time.Time
+	LastTeamActivity time.Time
+	SensitiveFiles   []string
+}
+
+// maxDigestLength is the longest description sent in one digest message.
+// Slack rejects section text over 3000 characters, the smallest limit of the supported notifiers.
+const maxDigestLength = 3000
+
+// digestTitle renders one of the global digest title templates.
+// They describe the whole digest, so only the team and the global settings are filled in.
+func digestTitle(cfg *config.Config, name string, staleAfter time.Duration) (string, error) {
+	data := templates.Data{
+		Team:           cfg.InternalTeam,
+		StaleAfter:     staleAfter,
+		PendingCIGrace: cfg.PendingCIGrace,
+	}
+	return templates.Render(name, cfg.Templates.Named()[name], data)
+}
+
+// digestPart is one message of a digest and the entries it lists
+type digestPart struct {
+	description string
+	entries     []digestEntry
+}
+
+// notifyDigest sends the entries that are due grouped by repo, split over several messages when they don't fit in one.
+// last_message_sent is only recorded for the entries of parts that were delivered.
+func notifyDigest(ctx context.Context, datastore database.Store, notifier notify.Notifier, cfg *config.Config, title string, entries []digestEntry) {
+	var due []digestEntry
+	for _, entry := range entries {
+		if dueForNotification(datastore, cfg.PolicyFor(entry.Owner+"/"+entry.Repo).RenotifyInterval, entry.Repo, entry.PRNumber) {
+			due = append(due, entry)
+		}
+	}
+	if len(due) == 0 {
+		slogs.Logr.Info("No pull requests are due for a digest message")
+		return
+	}
+
+	parts := digestParts(due, time.Now(), maxDigestLength)
+	slogs.Logr.Info("Sending digest message", "pull_requests", len(due), "parts", len(parts))
+	for i, part := range parts {
+		message := notify.Message{
+			Title:       fmt.Sprintf("%s (%d)", title, len(due)),
+			Description: part.description,
+		}
+		if len(parts) > 1 {
+			message.Title = fmt.Sprintf("%s (%d, part %d of %d)", title, len(due), i+1, len(parts))
+		}
+		if err := notifier.Notify(ctx, message); err != nil {
+			slogs.Logr.Error("Failed to send digest message", "part", i+1, "error", err)
+			continue
+		}
+		for _, entry := range part.entries {
+			recordNotification(datastore, entry.Repo, entry.PRNumber)
+		}
+	}
+	slogs.Logr.Info("Digest message sent", "pull_requests", len(due))
+}
+
+// digestParts lists the PRs under a heading per repo, in the order they were found, starting a new part before maxLength is exceeded
+func digestParts(entries []digestEntry, now time.Time, maxLength int) []digestPart {
+	var repos []string
+	byRepo := map[string][]digestEntry{}
+	for _, entry := range entries {
+		fullName := entry.Owner + "/" + entry.Repo
+		if _, ok := byRepo[fullName]; !ok {
+			repos = append(repos, fullName)
+		}
+		byRepo[fullName] = append(byRepo[fullName], entry)
+	}
+
+	var parts []digestPart
+	var current digestPart
+	var b strings.Builder
+	flush := func() {
+		if len(current.entries) == 0 {
+			return
+		}
+		current.description = strings.TrimSuffix(b.String(), "\n")
+		parts = append(parts, current)
+		current = digestPart{}
+		b.Reset()
+	}
+	for _, fullName := range repos {
+		headed := false
+		for _, entry := range byRepo[fullName] {
+			text := digestLine(entry, now)
+			if !headed {
+				text = fullName + "\n" + text
+				if b.Len() > 0 {
+					text = "\n" + text
+				}
+			}
+			if b.Len() > 0 && b.Len()+len(text) > maxLength {
+				flush()
+				text = fullName + "\n" + digestLine(entry, now)
+			}
+			if len(text) > maxLength {
+				// A single entry that doesn't fit on its own, e.g. one modifying hundreds of CI files
+				text = truncate(text, maxLength)
+			}
+			b.WriteString(text)
+			current.entries = append(current.entries, entry)
+			headed = true
+		}
+	}
+	flush()
+	return parts
+}
+
+// digestLine describes a single PR in a digest
+func digestLine(entry digestEntry, now time.Time) string {
+	activity := "no team activity"
+	if !entry.LastTeamActivity.IsZero() {
+		activity = fmt.Sprintf("last team activity %s ago", daysSince(entry.LastTeamActivity, now))
+	}
+	line := fmt.Sprintf("- #%d %s by %s, open %s, %s\n  %s\n", entry.PRNumber, entry.Title, entry.Author, daysSince(entry.CreatedAt, now), activity, entry.URL)
+	if len(entry.SensitiveFiles) > 0 {
+		line += fmt.Sprintf("  MODIFIES CI CONFIGURATION: %s\n", strings.Join(entry.SensitiveFiles, ", "))
+	}
+	return line
+}
+
+// truncate shortens s to at most maxLength bytes, ending it with an ellipsis and a newline
+func truncate(s string, maxLength int) string {
+	const ellipsis = "...\n"
+	if len(s) <= maxLength || maxLength < len(ellipsis) {
+		return s
+	}
+	cut := maxLength - len(ellipsis)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + ellipsis
+}
+
+func daysSince(t time.Time, now time.Time) string {
+	days := int(now.Sub(t).Hours() / 24)
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+// newNotifier creates the configured notifier, or in dry-run mode one that only records messages and needs no credentials
+func newNotifier(cfg config.NotifierConfig) (notify.Notifier, error) {
+	if recorder := dryRunRecorder(); recorder != nil {
+		return &dryrun.Notifier{Recorder: recorder, Type: cfg.Type}, nil
+	}
+	return notify.New(cfg)
+}
+
+// openDatastore connects to the database configured by the db-* flags, using the given table for PR state.
+// In dry-run mode the database is not touched at all and state is kept in memory instead.
+func openDatastore(table string) (database.Store, error) {
+	if recorder := dryRunRecorder(); recorder != nil {
+		return dryrun.NewStore(recorder, table), nil
+	}
+	return database.NewDatastore(databaseOptions(), table)
+}
+
+// openWelcomeStore connects to the database configured by the db-* flags for recording welcomed contributors.
+// It returns nil when welcome is disabled, and keeps welcomes in memory in dry-run mode.
+func openWelcomeStore(cfg *config.Config) (database.WelcomeStore, error) {
+	if !cfg.Welcome.Enabled {
+		return nil, nil
+	}
+	if recorder := dryRunRecorder(); recorder != nil {
+		return dryrun.NewWelcomeStore(recorder, welcomedContributorsTable), nil
+	}
+	return database.NewWelcomeStore(databaseOptions(), welcomedContributorsTable)
+}
+
+// openApprovalStore connects to the database configured by the db-* flags for recording auto-approved workflow runs.
+// It returns nil when auto_approve is disabled, and only records the inserts in dry-run mode.
+func openApprovalStore(cfg *config.Config) (database.ApprovalStore, error) {
+	if !cfg.AutoApprove.Enabled {
+		return nil, nil
+	}
+	if recorder := dryRunRecorder(); recorder != nil {
+		return dryrun.NewApprovalStore(recorder, autoApprovalsTable), nil
+	}
+	return database.NewApprovalStore(databaseOptions(), autoApprovalsTable)
+}
+
+// databaseOptions returns the connection settings from the db-* flags
+func databaseOptions() database.Options {
+	return database.Options{
+		Driver:  viper.GetString("db-driver"),
+		Host:    viper.GetString("db-host"),
+		Port:    viper.GetUint16("db-port"),
+		User:    viper.GetString("db-user"),
+		Pass:    viper.GetString("db-pass"),
+		Name:    viper.GetString("db-name"),
+		SSLMode: viper.GetString("db-sslmode"),
+		Path:    viper.GetString("db-path"),
+	}
+}
+
+// openAssignmentStore connects to the database configured by the db-* flags for recording review assignments.
+// In dry-run mode assignments are kept in memory instead.
+func openAssignmentStore() (database.AssignmentStore, error) {
+	if recorder := dryRunRecorder(); recorder != nil {
+		return dryrun.NewAssignmentStore(recorder, reviewAssignmentsTable), nil
+	}
+	return database.NewAssignmentStore(databaseOptions(), reviewAssignmentsTable)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/chia-network/github-bot/internal/config"
+	github2 "github.com/chia-network/github-bot/internal/github"
+	"github.com/chia-network/github-bot/internal/health"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+)
+
+var notifyStaleIssuesCmd = &cobra.Command{
+	Use:   "notify-stale-issues",
+	Short: "Sends a message to the configured chat channel, alerting that a community issue has had no team member activity within the stale window",
+	Run: func(cmd *cobra.Command, args []string) {
+		slogs.Init("info")
+		cfg, err := config.LoadConfig(viper.GetString("config"))
+		if err != nil {
+			slogs.Logr.Fatal("Error loading config", "error", err)
+		}
+		client, err := github2.NewClient(cfg, dryRunRecorder())
+		if err != nil {
+			slogs.Logr.Fatal("Error creating GitHub client", "error", err)
+		}
+		notifier, err := newNotifier(cfg.Jobs.NotifyStaleIssues.Notifier)
+		if err != nil {
+			slogs.Logr.Fatal("Error configuring notifier", "error", err)
+		}
+
+		datastore, err := openDatastore("stale_issue_status")
+
+		if err != nil {
+			slogs.Logr.Error("Could not initialize database connection", "error", err)
+			return
+		}
+		loop := viper.GetBool("loop")
+		loopDuration := viper.GetDuration("loop-time")
+		if loop {
+			health.RegisterJob("notify-stale-issues", loopDuration)
+			startStatusServer(cfg, client, datastore)
+		}
+		ctx := context.Background()
+		for {
+			err = runCycle(ctx, "notify-stale-issues", func(ctx context.Context) error {
+				return runNotifyStaleIssues(ctx, client, cfg, github2.NewSnapshot(client, cfg, 0), datastore, notifier)
+			})
+			if err != nil {
+				slogs.Logr.Error("Error notifying stale issues", "error", err)
+				time.Sleep(loopDuration)
+				continue
+			}
+
+			if !loop {
+				break
+			}
+
+			slogs.Logr.Info("Waiting for next iteration", "duration", loopDuration.String())
+			time.Sleep(loopDuration)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(notifyStaleIssuesCmd)
+}
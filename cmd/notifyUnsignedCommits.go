@@ -4,7 +4,6 @@ import (
 	"context"
 	"time"
 
-	"github.com/google/go-github/v60/github"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
@@ -12,11 +11,12 @@ import (
 
 	"github.com/chia-network/github-bot/internal/config"
 	github2 "github.com/chia-network/github-bot/internal/github"
+	"github.com/chia-network/github-bot/internal/health"
 )
 
 var notifyUnsignedCommitsCmd = &cobra.Command{
 	Use:   "notify-unsigned",
-	Short: "Provides a comment to the Pull Request author that unsigned commits are present",
+	Short: "Provides a comment to the Pull Request author that unsigned commits, or commits without a required sign-off, are present",
 	Run: func(cmd *cobra.Command, args []string) {
 		slogs.Init("info")
 		cfg, err := config.LoadConfig(viper.GetString("config"))
@@ -24,29 +24,29 @@ var notifyUnsignedCommitsCmd = &cobra.Command{
 			slogs.Logr.Fatal("Error loading config", "error", err)
 		}
 
-		client := github.NewClient(nil).WithAuthToken(cfg.GithubToken)
+		client, err := github2.NewClient(cfg, dryRunRecorder())
+		if err != nil {
+			slogs.Logr.Fatal("Error creating GitHub client", "error", err)
+		}
 
 		loop := viper.GetBool("loop")
 		loopDuration := viper.GetDuration("loop-time")
+		if loop {
+			health.RegisterJob("notify-unsigned", loopDuration)
+			startStatusServer(cfg, client)
+		}
 		ctx := context.Background()
 
 		for {
-			slogs.Logr.Info("Checking for community PRs that are waiting for CI to run")
-			listPendingPRs, err := github2.CheckUnsignedCommits(ctx, client, cfg)
+			err = runCycle(ctx, "notify-unsigned", func(ctx context.Context) error {
+				return runNotifyUnsigned(ctx, client, cfg, github2.NewSnapshot(client, cfg, 0))
+			})
 			if err != nil {
-				slogs.Logr.Error("Error obtaining a list of pending PRs", "error", err)
+				slogs.Logr.Error("Error commenting on PRs with unsigned commits", "error", err)
 				time.Sleep(loopDuration)
 				continue
 			}
 
-			for _, pr := range listPendingPRs {
-				err = github2.CheckAndComment(ctx, client, pr.Owner, pr.Repo, pr.PRNumber)
-				if err != nil {
-					slogs.Logr.Error("Error commenting on PR", "error", err, "repository", pr.Repo, "PR", pr.PRNumber)
-					continue
-				}
-			}
-
 			if !loop {
 				break
 			}
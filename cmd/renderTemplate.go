@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/chia-network/github-bot/internal/config"
+	github2 "github.com/chia-network/github-bot/internal/github"
+	"github.com/chia-network/github-bot/internal/templates"
+)
+
+var renderTemplateCmd = &cobra.Command{
+	Use:   "render-template <name>",
+	Short: "Previews a message or comment template from the config, rendered against a real PR or example data",
+	Long: `Previews a message or comment template from the config, rendered against a real PR or example data.
+
+Templates use Go text/template syntax. The available fields are:
+  .Repo .Owner .RepoName .Number .Title .Author .URL .Team
+  .CreatedAt .Age .LastTeamActivity .SinceTeamActivity .StaleAfter .PendingCIGrace
+  .Commits, each with .SHA .Author .Email .Reason .Description (unsigned_comment and signoff_comment only)
+  .SensitiveFiles (pending_ci_title and pending_ci_message only)
+  .ChangedLines (oversize_comment only)
+For stale_issue_title and stale_issue_message the fields describe the issue, and .StaleAfter is stale_issues.stale_after.
+The digest titles only get .Team, .StaleAfter and .PendingCIGrace.
+
+Templates can also use the functions days (formats a duration such as .Age as "N days"),
+short (abbreviates a SHA) and cell (escapes | for markdown tables).`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		slogs.Init("info")
+		cfg, err := config.LoadConfig(viper.GetString("config"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		repo, _ := cmd.Flags().GetString("repo")
+		prNumber, _ := cmd.Flags().GetInt("pr-number")
+		file, _ := cmd.Flags().GetString("file")
+
+		name := args[0]
+		named := cfg.PolicyFor(repo).Templates.Named()
+		text, ok := named[name]
+		if !ok {
+			names := make([]string, 0, len(named))
+			for n := range named {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			fmt.Fprintf(os.Stderr, "unknown template %q, expected one of %s\n", name, strings.Join(names, ", "))
+			os.Exit(1)
+		}
+		if file != "" {
+			contents, err := os.ReadFile(file)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error reading template: %v\n", err)
+				os.Exit(1)
+			}
+			text = string(contents)
+		}
+
+		data := templates.Fixture()
+		if prNumber != 0 {
+			owner, repoName, ok := strings.Cut(repo, "/")
+			if !ok {
+				fmt.Fprintln(os.Stderr, "--repo must be set as owner/repo to render against a real PR")
+				os.Exit(1)
+			}
+			client, err := github2.NewClient(cfg, dryRunRecorder())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error creating GitHub client: %v\n", err)
+				os.Exit(1)
+			}
+			snapshot := github2.NewSnapshot(client, cfg, 0)
+			if name == templates.StaleIssueTitle || name == templates.StaleIssueMessage {
+				data, err = github2.IssueTemplateData(context.Background(), client, cfg, snapshot, owner, repoName, prNumber)
+			} else {
+				data, err = github2.PRTemplateData(context.Background(), client, cfg, snapshot, name, owner, repoName, prNumber)
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+
+		out, err := templates.Render(name, text, data)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println(out)
+	},
+}
+
+func init() {
+	renderTemplateCmd.Flags().String("repo", "", "Repository whose templates to use, as owner/repo. Defaults to the global templates.")
+	renderTemplateCmd.Flags().Int("pr-number", 0, "Render against this PR in --repo instead of example data, or this issue for the stale issue templates")
+	renderTemplateCmd.Flags().String("file", "", "Render the template in this file instead of the one in the config")
+
+	rootCmd.AddCommand(renderTemplateCmd)
+}
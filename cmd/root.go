@@ -28,34 +28,52 @@ func Execute() {
 
 func init() {
 	var (
-		cfgFile  string
-		loop     bool
-		loopTime time.Duration
-		dbHost   string
-		dbPort   uint16
-		dbUser   string
-		dbPass   string
-		dbName   string
+		cfgFile     string
+		loop        bool
+		loopTime    time.Duration
+		dbDriver    string
+		dbHost      string
+		dbPort      uint16
+		dbUser      string
+		dbPass      string
+		dbName      string
+		dbSSLMode   string
+		dbPath      string
+		dryRun      bool
+		planPath    string
+		metricsAddr string
 	)
 
 	cobra.OnInitialize(initConfig)
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "config.yml", "config file to load")
 	rootCmd.PersistentFlags().BoolVar(&loop, "loop", false, "Use this var to periodically check on a loop")
 	rootCmd.PersistentFlags().DurationVar(&loopTime, "loop-time", 1*time.Hour, "The amount of time to wait between each iteration of the loop")
-	rootCmd.PersistentFlags().StringVar(&dbHost, "db-host", "127.0.0.1", "Hostname for MySQL")
-	rootCmd.PersistentFlags().Uint16Var(&dbPort, "db-port", 3306, "Port for MySQL")
-	rootCmd.PersistentFlags().StringVar(&dbUser, "db-user", "root", "User for MySQL")
-	rootCmd.PersistentFlags().StringVar(&dbPass, "db-pass", "root_password", "Password for MySQL")
-	rootCmd.PersistentFlags().StringVar(&dbName, "db-name", "github-bot", "Database name in MySQL")
+	rootCmd.PersistentFlags().StringVar(&dbDriver, "db-driver", "mysql", "Database to store notification state in: mysql, postgres or sqlite")
+	rootCmd.PersistentFlags().StringVar(&dbHost, "db-host", "127.0.0.1", "Hostname for MySQL or PostgreSQL")
+	rootCmd.PersistentFlags().Uint16Var(&dbPort, "db-port", 0, "Port for MySQL or PostgreSQL (defaults to 3306 for MySQL and 5432 for PostgreSQL)")
+	rootCmd.PersistentFlags().StringVar(&dbUser, "db-user", "root", "User for MySQL or PostgreSQL")
+	rootCmd.PersistentFlags().StringVar(&dbPass, "db-pass", "root_password", "Password for MySQL or PostgreSQL")
+	rootCmd.PersistentFlags().StringVar(&dbName, "db-name", "github-bot", "Database name in MySQL or PostgreSQL")
+	rootCmd.PersistentFlags().StringVar(&dbSSLMode, "db-sslmode", "disable", "sslmode for PostgreSQL connections")
+	rootCmd.PersistentFlags().StringVar(&dbPath, "db-path", "github-bot.db", "Path to the database file for SQLite")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Log labels, comments, messages and database writes instead of making them")
+	rootCmd.PersistentFlags().StringVar(&planPath, "dry-run-plan", "", "File to append each skipped dry-run action to as a line of JSON")
+	rootCmd.PersistentFlags().StringVar(&metricsAddr, "metrics-addr", ":9090", "Address to serve Prometheus metrics and the /healthz and /readyz endpoints on when looping or running as a daemon (empty to disable)")
 
 	cobra.CheckErr(viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config")))
 	cobra.CheckErr(viper.BindPFlag("loop", rootCmd.PersistentFlags().Lookup("loop")))
 	cobra.CheckErr(viper.BindPFlag("loop-time", rootCmd.PersistentFlags().Lookup("loop-time")))
+	cobra.CheckErr(viper.BindPFlag("db-driver", rootCmd.PersistentFlags().Lookup("db-driver")))
 	cobra.CheckErr(viper.BindPFlag("db-host", rootCmd.PersistentFlags().Lookup("db-host")))
 	cobra.CheckErr(viper.BindPFlag("db-port", rootCmd.PersistentFlags().Lookup("db-port")))
 	cobra.CheckErr(viper.BindPFlag("db-user", rootCmd.PersistentFlags().Lookup("db-user")))
 	cobra.CheckErr(viper.BindPFlag("db-pass", rootCmd.PersistentFlags().Lookup("db-pass")))
 	cobra.CheckErr(viper.BindPFlag("db-name", rootCmd.PersistentFlags().Lookup("db-name")))
+	cobra.CheckErr(viper.BindPFlag("db-sslmode", rootCmd.PersistentFlags().Lookup("db-sslmode")))
+	cobra.CheckErr(viper.BindPFlag("db-path", rootCmd.PersistentFlags().Lookup("db-path")))
+	cobra.CheckErr(viper.BindPFlag("dry-run", rootCmd.PersistentFlags().Lookup("dry-run")))
+	cobra.CheckErr(viper.BindPFlag("dry-run-plan", rootCmd.PersistentFlags().Lookup("dry-run-plan")))
+	cobra.CheckErr(viper.BindPFlag("metrics-addr", rootCmd.PersistentFlags().Lookup("metrics-addr")))
 }
 
 // initConfig reads in config file and ENV variables if set.
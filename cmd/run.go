@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/database"
+	github2 "github.com/chia-network/github-bot/internal/github"
+	"github.com/chia-network/github-bot/internal/health"
+	"github.com/chia-network/github-bot/internal/scheduler"
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Runs every job enabled in the config's jobs section in a single process, each on its own interval",
+	Run: func(cmd *cobra.Command, args []string) {
+		slogs.Init("info")
+		cfg, err := config.LoadConfig(viper.GetString("config"))
+		if err != nil {
+			slogs.Logr.Fatal("Error loading config", "error", err)
+		}
+		client, err := github2.NewClient(cfg, dryRunRecorder())
+		if err != nil {
+			slogs.Logr.Fatal("Error creating GitHub client", "error", err)
+		}
+		snapshot := github2.NewSnapshot(client, cfg, cfg.SnapshotTTL)
+		ctx := context.Background()
+
+		var jobs []scheduler.Job
+		var datastores []database.Pinger
+		if cfg.Jobs.LabelPRs.Enabled {
+			welcomeStore, err := openWelcomeStore(cfg)
+			if err != nil {
+				slogs.Logr.Fatal("Could not initialize database connection", "error", err)
+			}
+			if welcomeStore != nil {
+				datastores = append(datastores, welcomeStore)
+			}
+			jobs = append(jobs, scheduler.Job{
+				Name:     "label-prs",
+				Interval: cfg.Jobs.LabelPRs.Interval,
+				Run: func(ctx context.Context) error {
+					return runCycle(ctx, "label-prs", func(ctx context.Context) error {
+						return runLabelPRs(ctx, client, cfg, snapshot, welcomeStore)
+					})
+				},
+			})
+		}
+
+		if cfg.Jobs.NotifyPendingCI.Enabled {
+			notifier, err := newNotifier(cfg.Jobs.NotifyPendingCI.Notifier)
+			if err != nil {
+				slogs.Logr.Fatal("Error configuring notifier", "job", "notify-pendingci", "error", err)
+			}
+			datastore, err := openDatastore("pending_ci_status")
+			if err != nil {
+				slogs.Logr.Fatal("Could not initialize database connection", "error", err)
+			}
+			datastores = append(datastores, datastore)
+			approvalStore, err := openApprovalStore(cfg)
+			if err != nil {
+				slogs.Logr.Fatal("Could not initialize database connection", "error", err)
+			}
+			if approvalStore != nil {
+				datastores = append(datastores, approvalStore)
+			}
+			jobs = append(jobs, scheduler.Job{
+				Name:     "notify-pendingci",
+				Interval: cfg.Jobs.NotifyPendingCI.Interval,
+				Run: func(ctx context.Context) error {
+					return runCycle(ctx, "notify-pendingci", func(ctx context.Context) error {
+						return runNotifyPendingCI(ctx, client, cfg, snapshot, datastore, approvalStore, notifier)
+					})
+				},
+			})
+		}
+
+		if cfg.Jobs.NotifyStale.Enabled {
+			notifier, err := newNotifier(cfg.Jobs.NotifyStale.Notifier)
+			if err != nil {
+				slogs.Logr.Fatal("Error configuring notifier", "job", "notify-stale", "error", err)
+			}
+			datastore, err := openDatastore("stale_pr_status")
+			if err != nil {
+				slogs.Logr.Fatal("Could not initialize database connection", "error", err)
+			}
+			datastores = append(datastores, datastore)
+			jobs = append(jobs, scheduler.Job{
+				Name:     "notify-stale",
+				Interval: cfg.Jobs.NotifyStale.Interval,
+				Run: func(ctx context.Context) error {
+					return runCycle(ctx, "notify-stale", func(ctx context.Context) error {
+						return runNotifyStale(ctx, client, cfg, snapshot, datastore, notifier)
+					})
+				},
+			})
+		}
+
+		if cfg.Jobs.NotifyStaleIssues.Enabled {
+			notifier, err := newNotifier(cfg.Jobs.NotifyStaleIssues.Notifier)
+			if err != nil {
+				slogs.Logr.Fatal("Error configuring notifier", "job", "notify-stale-issues", "error", err)
+			}
+			datastore, err := openDatastore("stale_issue_status")
+			if err != nil {
+				slogs.Logr.Fatal("Could not initialize database connection", "error", err)
+			}
+			datastores = append(datastores, datastore)
+			jobs = append(jobs, scheduler.Job{
+				Name:     "notify-stale-issues",
+				Interval: cfg.Jobs.NotifyStaleIssues.Interval,
+				Run: func(ctx context.Context) error {
+					return runCycle(ctx, "notify-stale-issues", func(ctx context.Context) error {
+						return runNotifyStaleIssues(ctx, client, cfg, snapshot, datastore, notifier)
+					})
+				},
+			})
+		}
+
+		if cfg.Jobs.NotifyUnsigned.Enabled {
+			jobs = append(jobs, scheduler.Job{
+				Name:     "notify-unsigned",
+				Interval: cfg.Jobs.NotifyUnsigned.Interval,
+				Run: func(ctx context.Context) error {
+					return runCycle(ctx, "notify-unsigned", func(ctx context.Context) error {
+						return runNotifyUnsigned(ctx, client, cfg, snapshot)
+					})
+				},
+			})
+		}
+
+		if cfg.Jobs.AssignReviewers.Enabled {
+			store, err := openAssignmentStore()
+			if err != nil {
+				slogs.Logr.Fatal("Could not initialize database connection", "error", err)
+			}
+			datastores = append(datastores, store)
+			jobs = append(jobs, scheduler.Job{
+				Name:     "assign-reviewers",
+				Interval: cfg.Jobs.AssignReviewers.Interval,
+				Run: func(ctx context.Context) error {
+					return runCycle(ctx, "assign-reviewers", func(ctx context.Context) error {
+						return runAssignReviewers(ctx, client, cfg, snapshot, store)
+					})
+				},
+			})
+		}
+
+		if len(jobs) == 0 {
+			slogs.Logr.Fatal("No jobs are enabled in the jobs section of the config")
+		}
+
+		for _, job := range jobs {
+			health.RegisterJob(job.Name, job.Interval)
+		}
+		startStatusServer(cfg, client, datastores...)
+		scheduler.Run(ctx, jobs)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+}
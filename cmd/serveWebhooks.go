@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/google/go-github/v60/github"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/database"
+	github2 "github.com/chia-network/github-bot/internal/github"
+	"github.com/chia-network/github-bot/internal/health"
+	"github.com/chia-network/github-bot/internal/notify"
+	"github.com/chia-network/github-bot/internal/webhook"
+)
+
+var serveWebhooksCmd = &cobra.Command{
+	Use:   "serve-webhooks",
+	Short: "Listens for GitHub webhooks to label and check pull requests as they change, reconciling all PRs every loop-time",
+	Run: func(cmd *cobra.Command, args []string) {
+		slogs.Init("info")
+		cfg, err := config.LoadConfig(viper.GetString("config"))
+		if err != nil {
+			slogs.Logr.Fatal("Error loading config", "error", err)
+		}
+		if cfg.WebhookSecret == "" {
+			slogs.Logr.Fatal("webhook_secret must be set in the config to verify webhook deliveries")
+		}
+		client, err := github2.NewClient(cfg, dryRunRecorder())
+		if err != nil {
+			slogs.Logr.Fatal("Error creating GitHub client", "error", err)
+		}
+		notifier, err := newNotifier(cfg.Jobs.NotifyPendingCI.Notifier)
+		if err != nil {
+			slogs.Logr.Fatal("Error configuring notifier", "error", err)
+		}
+
+		datastore, err := openDatastore("pending_ci_status")
+		if err != nil {
+			slogs.Logr.Error("Could not initialize database connection", "error", err)
+			return
+		}
+
+		// Stale PR notifications run separately, but /bot commands can still snooze or suppress them
+		staleDatastore, err := openDatastore("stale_pr_status")
+		if err != nil {
+			slogs.Logr.Error("Could not initialize database connection", "error", err)
+			return
+		}
+		stores := map[string]database.Store{
+			"pendingci": datastore,
+			"stale":     staleDatastore,
+		}
+		welcomeStore, err := openWelcomeStore(cfg)
+		if err != nil {
+			slogs.Logr.Error("Could not initialize database connection", "error", err)
+			return
+		}
+		approvalStore, err := openApprovalStore(cfg)
+		if err != nil {
+			slogs.Logr.Error("Could not initialize database connection", "error", err)
+			return
+		}
+
+		ctx := context.Background()
+		onPendingCI := func(pr github2.PendingPR) {
+			notifyPendingPR(ctx, cfg, datastore, notifier, pr)
+		}
+
+		snapshot := github2.NewSnapshot(client, cfg, cfg.SnapshotTTL)
+		handler := webhook.NewHandler(cfg, client, snapshot, onPendingCI, stores, welcomeStore, approvalStore)
+		go handler.Run(github2.WithJob(ctx, "webhook"))
+
+		loopDuration := viper.GetDuration("loop-time")
+		health.RegisterJob("reconcile", loopDuration)
+		datastores := []database.Pinger{datastore}
+		if welcomeStore != nil {
+			datastores = append(datastores, welcomeStore)
+		}
+		if approvalStore != nil {
+			datastores = append(datastores, approvalStore)
+		}
+		startStatusServer(cfg, client, datastores...)
+		go func() {
+			for {
+				reconcile(ctx, client, cfg, snapshot, datastore, welcomeStore, approvalStore, notifier)
+				slogs.Logr.Info("Waiting for next reconciliation", "duration", loopDuration.String())
+				time.Sleep(loopDuration)
+			}
+		}()
+
+		mux := http.NewServeMux()
+		mux.Handle(viper.GetString("webhook-path"), handler)
+		server := &http.Server{
+			Addr:              viper.GetString("listen-addr"),
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		slogs.Logr.Info("Listening for webhooks", "address", server.Addr, "path", viper.GetString("webhook-path"))
+		if err := server.ListenAndServe(); err != nil {
+			slogs.Logr.Fatal("Webhook server stopped", "error", err)
+		}
+	},
+}
+
+// reconcile runs the polling versions of the webhook-driven checks, catching anything missed while deliveries were dropped or the server was down
+func reconcile(ctx context.Context, client *github.Client, cfg *config.Config, snapshot *github2.Snapshot, datastore database.Store, welcomeStore database.WelcomeStore, approvalStore database.ApprovalStore, notifier notify.Notifier) {
+	slogs.Logr.Info("Reconciling all pull requests")
+	snapshot.Invalidate()
+	// Webhook deliveries are summarized once per reconciliation so they show up alongside it
+	github2.LogAPIUsage("webhook")
+
+	_ = runCycle(ctx, "reconcile", func(ctx context.Context) error {
+		// Each check runs even if an earlier one failed; the cycle only counts as a success if all of them did
+		var errs []error
+		if err := runLabelPRs(ctx, client, cfg, snapshot, welcomeStore); err != nil {
+			slogs.Logr.Error("Error reconciling labels", "error", err)
+			errs = append(errs, err)
+		}
+		if err := runNotifyUnsigned(ctx, client, cfg, snapshot); err != nil {
+			slogs.Logr.Error("Error reconciling unsigned commits", "error", err)
+			errs = append(errs, err)
+		}
+		if err := runNotifyPendingCI(ctx, client, cfg, snapshot, datastore, approvalStore, notifier); err != nil {
+			slogs.Logr.Error("Error reconciling pending CI", "error", err)
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	})
+}
+
+func init() {
+	serveWebhooksCmd.Flags().String("listen-addr", ":8080", "Address to listen on for GitHub webhook deliveries")
+	serveWebhooksCmd.Flags().String("webhook-path", "/webhook", "HTTP path GitHub delivers webhooks to")
+
+	cobra.CheckErr(viper.BindPFlag("listen-addr", serveWebhooksCmd.Flags().Lookup("listen-addr")))
+	cobra.CheckErr(viper.BindPFlag("webhook-path", serveWebhooksCmd.Flags().Lookup("webhook-path")))
+
+	rootCmd.AddCommand(serveWebhooksCmd)
+}
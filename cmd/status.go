@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/google/go-github/v60/github"
+	"github.com/spf13/viper"
+
+	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/database"
+	"github.com/chia-network/github-bot/internal/health"
+	"github.com/chia-network/github-bot/internal/metrics"
+)
+
+// startStatusServer serves /metrics, /healthz and /readyz on --metrics-addr in the background.
+// Readiness checks that GitHub and any of the given datastores are reachable.
+func startStatusServer(cfg *config.Config, client *github.Client, datastores ...database.Pinger) {
+	health.Configure(cfg.Health)
+	health.AddCheck("github", func(ctx context.Context) error {
+		// The rate limit endpoint doesn't count against the rate limit
+		_, _, err := client.RateLimit.Get(ctx)
+		return err
+	})
+	if len(datastores) > 0 {
+		health.AddCheck("database", func(ctx context.Context) error {
+			var errs []error
+			for _, datastore := range datastores {
+				errs = append(errs, datastore.Ping(ctx))
+			}
+			return errors.Join(errs...)
+		})
+	}
+
+	addr := viper.GetString("metrics-addr")
+	if addr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/healthz", health.Handler())
+	mux.Handle("/readyz", health.Handler())
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		slogs.Logr.Info("Serving metrics and health checks", "address", addr)
+		if err := server.ListenAndServe(); err != nil {
+			slogs.Logr.Error("Status server stopped", "error", err)
+		}
+	}()
+}
@@ -7,8 +7,6 @@ import (
 	"github.com/chia-network/go-modules/pkg/slogs"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-
-	"github.com/chia-network/github-bot/internal/database"
 )
 
 var updateSuppressCmd = &cobra.Command{
@@ -36,16 +34,9 @@ var updateSuppressCmd = &cobra.Command{
 		}
 
 		slogs.Logr.Info("Connecting to the database")
-		datastore, err := database.NewDatastore(
-			viper.GetString("db-host"),
-			viper.GetUint16("db-port"),
-			viper.GetString("db-user"),
-			viper.GetString("db-pass"),
-			viper.GetString("db-name"),
-			table,
-		)
+		datastore, err := openDatastore(table)
 		if err != nil {
-			slogs.Logr.Error("Could not initialize MySQL connection", "error", err)
+			slogs.Logr.Error("Could not initialize database connection", "error", err)
 			return
 		}
 
@@ -1,24 +1,354 @@
 package config
 
+import (
+	"strings"
+	"time"
+
+	"github.com/chia-network/github-bot/internal/templates"
+)
+
 // Config defines the config for all aspects of the bot
 type Config struct {
-	GithubToken              string   `yaml:"github_token"`
-	InternalTeam             string   `yaml:"internal_team"`
-	InternalTeamIgnoredUsers []string `yaml:"internal_team_ignored_users"`
-	SkipUsers                []string `yaml:"skip_users"`
-	SkipUsersMap             map[string]bool
-	LabelConfig              `yaml:",inline"`
-	CheckRepos               []CheckRepo `yaml:"check_repos"`
+	GithubToken              string          `yaml:"github_token"`
+	GithubApp                GithubAppConfig `yaml:"github_app"`
+	WebhookSecret            string          `yaml:"webhook_secret"`
+	InternalTeam             string          `yaml:"internal_team"`
+	InternalTeamIgnoredUsers []string        `yaml:"internal_team_ignored_users"`
+	SkipUsers                []string        `yaml:"skip_users"`
+	SkipUsersMap             map[string]bool `yaml:"-"`
+	RepoPolicy               `yaml:",inline"`
+	CheckRepos               []CheckRepo       `yaml:"check_repos"`
+	SnapshotTTL              time.Duration     `yaml:"snapshot_ttl"`
+	Jobs                     JobsConfig        `yaml:"jobs"`
+	Health                   HealthConfig      `yaml:"health"`
+	AutoApprove              AutoApproveConfig `yaml:"auto_approve"`
+	Reviewers                ReviewersConfig   `yaml:"reviewers"`
+	Welcome                  WelcomeConfig     `yaml:"welcome"`
 }
 
 // LabelConfig is the configuration options specific to labeling PRs
 type LabelConfig struct {
 	LabelInternal string `yaml:"label_internal"`
 	LabelExternal string `yaml:"label_external"`
+	// LabelSensitive warns maintainers that a community PR awaiting CI approval changes sensitive_paths
+	LabelSensitive string `yaml:"label_sensitive"`
+}
+
+// RepoPolicy holds the settings that are set globally and can be overridden for individual repos in check_repos
+type RepoPolicy struct {
+	LabelConfig `yaml:",inline"`
+	// StaleAfter is how long a community PR can go without team member activity before it is reported as stale
+	StaleAfter time.Duration `yaml:"stale_after"`
+	// PendingCIGrace is how long to wait after the last commit before reporting workflows awaiting approval
+	PendingCIGrace time.Duration `yaml:"pending_ci_grace"`
+	// RenotifyInterval is the minimum time between repeated notifications about the same PR
+	RenotifyInterval time.Duration   `yaml:"renotify_interval"`
+	Templates        TemplatesConfig `yaml:"templates"`
+	// SensitivePaths are globs for CI configuration and other files a community PR can use to run code with the repo's secrets.
+	// PRs that change them are never auto-approved, and are flagged in pending CI notifications.
+	SensitivePaths []string `yaml:"sensitive_paths"`
+	// PathLabels label every open PR by the files it changes
+	PathLabels  []PathLabelRule   `yaml:"path_labels"`
+	SizeLabels  SizeLabelsConfig  `yaml:"size_labels"`
+	StaleIssues StaleIssuesConfig `yaml:"stale_issues"`
+	// RequireSignoff also checks every commit has a Developer Certificate of Origin Signed-off-by trailer matching its author.
+	// A repo that leaves it unset inherits the global value, and can set it to false to opt out. Read it with SignoffRequired.
+	RequireSignoff *bool `yaml:"require_signoff"`
+	// SignedCommitsCheck publishes the signed commits result as a check on every PR, instead of the unsigned_comment, so it can
+	// be required in branch protection. It is inherited like RequireSignoff. Read it with SignedCommitsChecked.
+	SignedCommitsCheck *bool `yaml:"signed_commits_check"`
+}
+
+// SignoffRequired reports whether require_signoff is on
+func (p RepoPolicy) SignoffRequired() bool {
+	return p.RequireSignoff != nil && *p.RequireSignoff
+}
+
+// SignedCommitsChecked reports whether signed_commits_check is on
+func (p RepoPolicy) SignedCommitsChecked() bool {
+	return p.SignedCommitsCheck != nil && *p.SignedCommitsCheck
+}
+
+// StaleIssuesConfig controls which community issues notify-stale-issues reports
+type StaleIssuesConfig struct {
+	// StaleAfter is how long a community issue can go without team member activity before it is reported; defaults to stale_after
+	StaleAfter time.Duration `yaml:"stale_after"`
+	// ExcludeLabels keep issues with any of them, e.g. accepted or blocked, from being reported
+	ExcludeLabels []string `yaml:"exclude_labels"`
+}
+
+// StaleIssuesAfter returns stale_issues.stale_after, falling back to stale_after when it isn't set
+func (p RepoPolicy) StaleIssuesAfter() time.Duration {
+	if p.StaleIssues.StaleAfter != 0 {
+		return p.StaleIssues.StaleAfter
+	}
+	return p.StaleAfter
+}
+
+// SizeLabelsConfig labels every open PR by the number of lines it changes, and asks community PRs that are too big to be split
+type SizeLabelsConfig struct {
+	// Labels are applied by size; a PR gets the label with the largest min that its changed lines reach
+	Labels []SizeLabel `yaml:"labels"`
+	// Exclude are globs for generated, lock and vendored files that don't count towards the size
+	Exclude []string `yaml:"exclude"`
+	// OversizeThreshold is the number of changed lines at which community PRs get a one-time oversize_comment; 0 disables it
+	OversizeThreshold int `yaml:"oversize_threshold"`
+}
+
+// SizeLabel is applied to PRs with at least Min changed lines
+type SizeLabel struct {
+	Label string `yaml:"label"`
+	Min   int    `yaml:"min"`
+}
+
+// PathLabelRule adds Label to PRs that change a file matching any of the Paths globs, and removes it once they no longer do
+type PathLabelRule struct {
+	Label string   `yaml:"label"`
+	Paths []string `yaml:"paths"`
+}
+
+// TemplatesConfig holds the text/template sources for the bot's messages and comments.
+// The data available to them is documented on templates.Data.
+type TemplatesConfig struct {
+	StaleTitle       string `yaml:"stale_title"`
+	StaleMessage     string `yaml:"stale_message"`
+	PendingCITitle   string `yaml:"pending_ci_title"`
+	PendingCIMessage string `yaml:"pending_ci_message"`
+	UnsignedComment  string `yaml:"unsigned_comment"`
+	OversizeComment  string `yaml:"oversize_comment"`
+	WelcomeComment   string `yaml:"welcome_comment"`
+	SignoffComment   string `yaml:"signoff_comment"`
+	// StaleIssueTitle and StaleIssueMessage are sent by notify-stale-issues
+	StaleIssueTitle   string `yaml:"stale_issue_title"`
+	StaleIssueMessage string `yaml:"stale_issue_message"`
+	// The digest titles are used when a notify job has digest set, and can only be set in the global templates
+	StaleDigestTitle      string `yaml:"stale_digest_title"`
+	PendingCIDigestTitle  string `yaml:"pending_ci_digest_title"`
+	StaleIssueDigestTitle string `yaml:"stale_issue_digest_title"`
+	// AutoApprovedTitle announces that a PR's workflow runs were approved automatically
+	AutoApprovedTitle string `yaml:"auto_approved_title"`
+}
+
+// Named returns each template keyed by its name in the config
+func (t TemplatesConfig) Named() map[string]string {
+	return map[string]string{
+		templates.StaleTitle:            t.StaleTitle,
+		templates.StaleMessage:          t.StaleMessage,
+		templates.PendingCITitle:        t.PendingCITitle,
+		templates.PendingCIMessage:      t.PendingCIMessage,
+		templates.UnsignedComment:       t.UnsignedComment,
+		templates.OversizeComment:       t.OversizeComment,
+		templates.WelcomeComment:        t.WelcomeComment,
+		templates.SignoffComment:        t.SignoffComment,
+		templates.StaleIssueTitle:       t.StaleIssueTitle,
+		templates.StaleIssueMessage:     t.StaleIssueMessage,
+		templates.StaleDigestTitle:      t.StaleDigestTitle,
+		templates.PendingCIDigestTitle:  t.PendingCIDigestTitle,
+		templates.StaleIssueDigestTitle: t.StaleIssueDigestTitle,
+		templates.AutoApprovedTitle:     t.AutoApprovedTitle,
+	}
+}
+
+// merge overrides each template with the one from other when it is set. The digest titles are left alone since
+// Validate rejects them on check_repos entries.
+func (t TemplatesConfig) merge(other TemplatesConfig) TemplatesConfig {
+	if other.StaleTitle != "" {
+		t.StaleTitle = other.StaleTitle
+	}
+	if other.StaleMessage != "" {
+		t.StaleMessage = other.StaleMessage
+	}
+	if other.PendingCITitle != "" {
+		t.PendingCITitle = other.PendingCITitle
+	}
+	if other.PendingCIMessage != "" {
+		t.PendingCIMessage = other.PendingCIMessage
+	}
+	if other.UnsignedComment != "" {
+		t.UnsignedComment = other.UnsignedComment
+	}
+	if other.OversizeComment != "" {
+		t.OversizeComment = other.OversizeComment
+	}
+	if other.WelcomeComment != "" {
+		t.WelcomeComment = other.WelcomeComment
+	}
+	if other.SignoffComment != "" {
+		t.SignoffComment = other.SignoffComment
+	}
+	if other.StaleIssueTitle != "" {
+		t.StaleIssueTitle = other.StaleIssueTitle
+	}
+	if other.StaleIssueMessage != "" {
+		t.StaleIssueMessage = other.StaleIssueMessage
+	}
+	if other.AutoApprovedTitle != "" {
+		t.AutoApprovedTitle = other.AutoApprovedTitle
+	}
+	return t
+}
+
+// GithubAppConfig authenticates as a GitHub App instead of with github_token
+type GithubAppConfig struct {
+	AppID int64 `yaml:"app_id"`
+	// PrivateKey is the PEM encoded key; PrivateKeyPath is read instead when it is empty
+	PrivateKey     string `yaml:"private_key"`
+	PrivateKeyPath string `yaml:"private_key_path"`
+}
+
+// JobsConfig enables and schedules the jobs hosted by the run command
+type JobsConfig struct {
+	LabelPRs        JobConfig `yaml:"label_prs"`
+	NotifyPendingCI JobConfig `yaml:"notify_pendingci"`
+	NotifyStale     JobConfig `yaml:"notify_stale"`
+	NotifyUnsigned  JobConfig `yaml:"notify_unsigned"`
+	AssignReviewers JobConfig `yaml:"assign_reviewers"`
+	// NotifyStaleIssues reports community issues, where notify_stale reports PRs
+	NotifyStaleIssues JobConfig `yaml:"notify_stale_issues"`
+}
+
+// JobConfig is the schedule for a single job in the run command, and where the job sends its notifications
+type JobConfig struct {
+	Enabled  bool           `yaml:"enabled"`
+	Interval time.Duration  `yaml:"interval"`
+	Notifier NotifierConfig `yaml:"notifier"`
+	// Digest sends every PR found in a cycle as one message grouped by repo instead of one message per PR.
+	// Only notify_pendingci, notify_stale and notify_stale_issues send digests.
+	Digest bool `yaml:"digest"`
+}
+
+// NotifierConfig selects and configures the chat service a job's messages are delivered to
+type NotifierConfig struct {
+	// Type is one of keybase (the default), slack, discord, matrix or webhook
+	Type string `yaml:"type"`
+	// URL is the incoming webhook URL for keybase, slack, discord and webhook notifiers
+	URL string `yaml:"url"`
+	// Token is the bearer token for keybase or the access token for matrix
+	Token string `yaml:"token"`
+	// Homeserver and RoomID are only used by the matrix notifier
+	Homeserver string `yaml:"homeserver"`
+	RoomID     string `yaml:"room_id"`
+	// Headers are added to every request sent by the webhook notifier
+	Headers map[string]string `yaml:"headers"`
+}
+
+// AutoApproveConfig approves fork workflow runs awaiting approval when the PR's author is trusted and it changes no sensitive paths.
+// An author is trusted if any of the criteria match.
+type AutoApproveConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// AllowedUsers are always trusted
+	AllowedUsers []string `yaml:"allowed_users"`
+	// OrgMembers trusts members of the organization that owns the repo
+	OrgMembers bool `yaml:"org_members"`
+	// MinMergedPRs trusts authors with at least this many merged PRs in the repo; 0 disables the check
+	MinMergedPRs int `yaml:"min_merged_prs"`
+}
+
+// The reviewer selection strategies
+const (
+	ReviewStrategyRoundRobin  = "round_robin"
+	ReviewStrategyLeastLoaded = "least_loaded"
+)
+
+// ReviewersConfig controls how the assign_reviewers job picks internal team members from CODEOWNERS to review community PRs
+type ReviewersConfig struct {
+	// Strategy is round_robin, which picks the owners assigned least recently, or least_loaded, which picks the owners
+	// with the fewest assignments within load_window
+	Strategy string `yaml:"strategy"`
+	// Count is how many reviewers to request on each PR
+	Count int `yaml:"count"`
+	// LoadWindow is how far back the bot's earlier assignments are taken into account
+	LoadWindow time.Duration `yaml:"load_window"`
+}
+
+// WelcomeConfig greets community authors with the welcome_comment on their first pull request to a repo, which the label-prs
+// job and webhooks check for
+type WelcomeConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Label is added to the pull request the author is welcomed on
+	Label string `yaml:"label"`
+}
+
+// HealthConfig controls the /healthz and /readyz endpoints and the heartbeat sent after each successful cycle
+type HealthConfig struct {
+	// Window is how long a job may go without a successful cycle before it is reported unhealthy.
+	// When 0, each job gets three of its intervals.
+	Window time.Duration `yaml:"window"`
+	// HeartbeatURL is requested after every successful cycle, with {job} replaced by the job's name
+	HeartbeatURL string `yaml:"heartbeat_url"`
 }
 
 // CheckRepo is config settings when checking a repo
 type CheckRepo struct {
 	Name          string `yaml:"name"`
 	MinimumNumber int    `yaml:"minimum_number"`
+	// RepoPolicy overrides the global settings for this repo; anything left empty is inherited
+	RepoPolicy `yaml:",inline"`
+}
+
+// FindCheckRepo returns the CheckRepo entry for the given owner/repo name, if it is configured
+func (c *Config) FindCheckRepo(fullName string) (CheckRepo, bool) {
+	for _, repo := range c.CheckRepos {
+		if strings.EqualFold(repo.Name, fullName) {
+			return repo, true
+		}
+	}
+	return CheckRepo{}, false
+}
+
+// PolicyFor returns the global settings with any overrides from the repo's check_repos entry applied
+func (c *Config) PolicyFor(fullName string) RepoPolicy {
+	policy := c.RepoPolicy
+	repo, ok := c.FindCheckRepo(fullName)
+	if !ok {
+		return policy
+	}
+
+	if repo.LabelInternal != "" {
+		policy.LabelInternal = repo.LabelInternal
+	}
+	if repo.LabelExternal != "" {
+		policy.LabelExternal = repo.LabelExternal
+	}
+	if repo.LabelSensitive != "" {
+		policy.LabelSensitive = repo.LabelSensitive
+	}
+	if repo.StaleAfter != 0 {
+		policy.StaleAfter = repo.StaleAfter
+	}
+	if repo.PendingCIGrace != 0 {
+		policy.PendingCIGrace = repo.PendingCIGrace
+	}
+	if repo.RenotifyInterval != 0 {
+		policy.RenotifyInterval = repo.RenotifyInterval
+	}
+	policy.Templates = policy.Templates.merge(repo.Templates)
+	if len(repo.SensitivePaths) > 0 {
+		policy.SensitivePaths = repo.SensitivePaths
+	}
+	if len(repo.PathLabels) > 0 {
+		policy.PathLabels = repo.PathLabels
+	}
+	if len(repo.SizeLabels.Labels) > 0 {
+		policy.SizeLabels.Labels = repo.SizeLabels.Labels
+	}
+	if len(repo.SizeLabels.Exclude) > 0 {
+		policy.SizeLabels.Exclude = repo.SizeLabels.Exclude
+	}
+	if repo.SizeLabels.OversizeThreshold != 0 {
+		policy.SizeLabels.OversizeThreshold = repo.SizeLabels.OversizeThreshold
+	}
+	if repo.StaleIssues.StaleAfter != 0 {
+		policy.StaleIssues.StaleAfter = repo.StaleIssues.StaleAfter
+	}
+	if len(repo.StaleIssues.ExcludeLabels) > 0 {
+		policy.StaleIssues.ExcludeLabels = repo.StaleIssues.ExcludeLabels
+	}
+	if repo.RequireSignoff != nil {
+		policy.RequireSignoff = repo.RequireSignoff
+	}
+	if repo.SignedCommitsCheck != nil {
+		policy.SignedCommitsCheck = repo.SignedCommitsCheck
+	}
+	return policy
 }
@@ -1,12 +1,19 @@
 package config
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/chia-network/github-bot/internal/templates"
 )
 
-// LoadConfig loads config from the given path
+// LoadConfig loads config from the given path, rejecting unknown keys and invalid values
 func LoadConfig(path string) (*Config, error) {
 	configBytes, err := os.ReadFile(path)
 	if err != nil {
@@ -15,9 +22,11 @@ func LoadConfig(path string) (*Config, error) {
 
 	config := &Config{}
 
-	err = yaml.Unmarshal(configBytes, config)
-	if err != nil {
-		return nil, err
+	decoder := yaml.NewDecoder(bytes.NewReader(configBytes))
+	decoder.KnownFields(true)
+	err = decoder.Decode(config)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
 	}
 
 	config.SkipUsersMap = map[string]bool{}
@@ -25,5 +34,59 @@ func LoadConfig(path string) (*Config, error) {
 		config.SkipUsersMap[user] = true
 	}
 
+	if config.StaleAfter == 0 {
+		config.StaleAfter = 7 * 24 * time.Hour
+	}
+	if config.PendingCIGrace == 0 {
+		config.PendingCIGrace = 2 * time.Hour
+	}
+	if config.RenotifyInterval == 0 {
+		config.RenotifyInterval = 24 * time.Hour
+	}
+	config.Templates = TemplatesConfig{
+		StaleTitle:            templates.DefaultStaleTitle,
+		StaleMessage:          templates.DefaultStaleMessage,
+		PendingCITitle:        templates.DefaultPendingCITitle,
+		PendingCIMessage:      templates.DefaultPendingCIMessage,
+		UnsignedComment:       templates.DefaultUnsignedComment,
+		OversizeComment:       templates.DefaultOversizeComment,
+		WelcomeComment:        templates.DefaultWelcomeComment,
+		SignoffComment:        templates.DefaultSignoffComment,
+		StaleIssueTitle:       templates.DefaultStaleIssueTitle,
+		StaleIssueMessage:     templates.DefaultStaleIssueMessage,
+		StaleDigestTitle:      templates.DefaultStaleDigestTitle,
+		PendingCIDigestTitle:  templates.DefaultPendingCIDigestTitle,
+		StaleIssueDigestTitle: templates.DefaultStaleIssueDigestTitle,
+		AutoApprovedTitle:     templates.DefaultAutoApprovedTitle,
+	}.merge(config.Templates)
+	if len(config.SensitivePaths) == 0 {
+		config.SensitivePaths = []string{".github/workflows/**", ".github/actions/**", "**/Makefile", "**/Dockerfile", "**/*.sh"}
+	}
+	if config.SnapshotTTL == 0 {
+		config.SnapshotTTL = 5 * time.Minute
+	}
+	if config.Welcome.Label == "" {
+		config.Welcome.Label = "first-time-contributor"
+	}
+	if config.Reviewers.Strategy == "" {
+		config.Reviewers.Strategy = ReviewStrategyRoundRobin
+	}
+	if config.Reviewers.Count == 0 {
+		config.Reviewers.Count = 1
+	}
+	if config.Reviewers.LoadWindow == 0 {
+		config.Reviewers.LoadWindow = 30 * 24 * time.Hour
+	}
+	for _, job := range []*JobConfig{&config.Jobs.LabelPRs, &config.Jobs.NotifyPendingCI, &config.Jobs.NotifyStale, &config.Jobs.NotifyUnsigned, &config.Jobs.AssignReviewers, &config.Jobs.NotifyStaleIssues} {
+		if job.Interval == 0 {
+			job.Interval = time.Hour
+		}
+	}
+
+	err = config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config in %s:\n%w", path, err)
+	}
+
 	return config, nil
 }
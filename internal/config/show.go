@@ -0,0 +1,49 @@
+package config
+
+const redacted = "REDACTED"
+
+// Resolved returns a copy of the config with every check_repos entry's policy filled in from the global settings
+func (c *Config) Resolved() *Config {
+	resolved := *c
+	resolved.CheckRepos = make([]CheckRepo, len(c.CheckRepos))
+	for i, repo := range c.CheckRepos {
+		repo.RepoPolicy = c.PolicyFor(repo.Name)
+		resolved.CheckRepos[i] = repo
+	}
+	return &resolved
+}
+
+// Redacted returns a copy of the config with tokens, keys and webhook URLs replaced so it is safe to print
+func (c *Config) Redacted() *Config {
+	out := *c
+	out.GithubToken = redact(out.GithubToken)
+	out.GithubApp.PrivateKey = redact(out.GithubApp.PrivateKey)
+	out.WebhookSecret = redact(out.WebhookSecret)
+	// Heartbeat URLs usually identify the check they ping, which is enough to fake it
+	out.Health.HeartbeatURL = redact(out.Health.HeartbeatURL)
+	for _, job := range []*JobConfig{&out.Jobs.LabelPRs, &out.Jobs.NotifyPendingCI, &out.Jobs.NotifyStale, &out.Jobs.NotifyUnsigned, &out.Jobs.AssignReviewers, &out.Jobs.NotifyStaleIssues} {
+		job.Notifier = job.Notifier.redacted()
+	}
+	return &out
+}
+
+func (n NotifierConfig) redacted() NotifierConfig {
+	// Incoming webhook URLs embed their credentials, so they are as sensitive as the tokens
+	n.URL = redact(n.URL)
+	n.Token = redact(n.Token)
+	if n.Headers != nil {
+		headers := make(map[string]string, len(n.Headers))
+		for name, value := range n.Headers {
+			headers[name] = redact(value)
+		}
+		n.Headers = headers
+	}
+	return n
+}
+
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redacted
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+
+	"github.com/chia-network/github-bot/internal/templates"
+)
+
+var notifierTypes = map[string]bool{"": true, "keybase": true, "slack": true, "discord": true, "matrix": true, "webhook": true}
+
+var reviewStrategies = map[string]bool{ReviewStrategyRoundRobin: true, ReviewStrategyLeastLoaded: true}
+
+// Validate checks the config for problems that can be found without talking to GitHub, returning all of them joined together
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.GithubApp.AppID != 0 && c.GithubApp.PrivateKey == "" && c.GithubApp.PrivateKeyPath == "" {
+		errs = append(errs, fmt.Errorf("github_app: private_key or private_key_path is required"))
+	}
+	if c.GithubApp.AppID == 0 && (c.GithubApp.PrivateKey != "" || c.GithubApp.PrivateKeyPath != "") {
+		errs = append(errs, fmt.Errorf("github_app: app_id is required when a private key is set"))
+	}
+
+	if c.InternalTeam != "" && !isSlugPair(c.InternalTeam) {
+		errs = append(errs, fmt.Errorf("internal_team: %q must be in the form org/team", c.InternalTeam))
+	}
+
+	errs = append(errs, c.RepoPolicy.validate("")...)
+
+	seen := map[string]bool{}
+	for i, repo := range c.CheckRepos {
+		field := fmt.Sprintf("check_repos[%d]", i)
+		if !isSlugPair(repo.Name) {
+			errs = append(errs, fmt.Errorf("%s: name %q must be in the form owner/repo", field, repo.Name))
+		}
+		key := strings.ToLower(repo.Name)
+		if seen[key] {
+			errs = append(errs, fmt.Errorf("%s: %s is listed more than once", field, repo.Name))
+		}
+		seen[key] = true
+		if repo.MinimumNumber < 0 {
+			errs = append(errs, fmt.Errorf("%s: minimum_number must not be negative", field))
+		}
+		errs = append(errs, repo.RepoPolicy.validate(field+": ")...)
+		// Digests cover every repo, so their titles only come from the global templates
+		digestTitles := []struct{ name, source string }{
+			{templates.StaleDigestTitle, repo.Templates.StaleDigestTitle},
+			{templates.PendingCIDigestTitle, repo.Templates.PendingCIDigestTitle},
+			{templates.StaleIssueDigestTitle, repo.Templates.StaleIssueDigestTitle},
+		}
+		for _, title := range digestTitles {
+			if title.source != "" {
+				errs = append(errs, fmt.Errorf("%s: templates.%s can only be set globally", field, title.name))
+			}
+		}
+	}
+
+	if c.SnapshotTTL < 0 {
+		errs = append(errs, fmt.Errorf("snapshot_ttl must not be negative"))
+	}
+	if c.Health.Window < 0 {
+		errs = append(errs, fmt.Errorf("health.window must not be negative"))
+	}
+	if c.AutoApprove.MinMergedPRs < 0 {
+		errs = append(errs, fmt.Errorf("auto_approve.min_merged_prs must not be negative"))
+	}
+	if c.AutoApprove.Enabled && len(c.AutoApprove.AllowedUsers) == 0 && !c.AutoApprove.OrgMembers && c.AutoApprove.MinMergedPRs == 0 {
+		errs = append(errs, fmt.Errorf("auto_approve: at least one of allowed_users, org_members or min_merged_prs is required when enabled"))
+	}
+
+	if !reviewStrategies[c.Reviewers.Strategy] {
+		errs = append(errs, fmt.Errorf("reviewers: unknown strategy %q", c.Reviewers.Strategy))
+	}
+	if c.Reviewers.Count < 0 {
+		errs = append(errs, fmt.Errorf("reviewers.count must not be negative"))
+	}
+	if c.Reviewers.LoadWindow < 0 {
+		errs = append(errs, fmt.Errorf("reviewers.load_window must not be negative"))
+	}
+
+	jobs := map[string]JobConfig{
+		"label_prs":           c.Jobs.LabelPRs,
+		"notify_pendingci":    c.Jobs.NotifyPendingCI,
+		"notify_stale":        c.Jobs.NotifyStale,
+		"notify_unsigned":     c.Jobs.NotifyUnsigned,
+		"assign_reviewers":    c.Jobs.AssignReviewers,
+		"notify_stale_issues": c.Jobs.NotifyStaleIssues,
+	}
+	for _, name := range []string{"label_prs", "notify_pendingci", "notify_stale", "notify_unsigned", "assign_reviewers", "notify_stale_issues"} {
+		job := jobs[name]
+		if job.Interval < 0 {
+			errs = append(errs, fmt.Errorf("jobs.%s: interval must not be negative", name))
+		}
+		if !notifierTypes[job.Notifier.Type] {
+			errs = append(errs, fmt.Errorf("jobs.%s: unknown notifier type %q", name, job.Notifier.Type))
+		}
+		if job.Notifier.Type == "matrix" && (job.Notifier.Homeserver == "" || job.Notifier.RoomID == "") {
+			errs = append(errs, fmt.Errorf("jobs.%s: matrix notifier requires homeserver and room_id", name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p RepoPolicy) validate(prefix string) []error {
+	var errs []error
+	if p.StaleAfter < 0 {
+		errs = append(errs, fmt.Errorf("%sstale_after must not be negative", prefix))
+	}
+	if p.StaleIssues.StaleAfter < 0 {
+		errs = append(errs, fmt.Errorf("%sstale_issues.stale_after must not be negative", prefix))
+	}
+	if p.PendingCIGrace < 0 {
+		errs = append(errs, fmt.Errorf("%spending_ci_grace must not be negative", prefix))
+	}
+	if p.RenotifyInterval < 0 {
+		errs = append(errs, fmt.Errorf("%srenotify_interval must not be negative", prefix))
+	}
+	for _, pattern := range p.SensitivePaths {
+		if !doublestar.ValidatePattern(pattern) {
+			errs = append(errs, fmt.Errorf("%ssensitive_paths: %q is not a valid glob", prefix, pattern))
+		}
+	}
+	for i, rule := range p.PathLabels {
+		field := fmt.Sprintf("%spath_labels[%d]", prefix, i)
+		if rule.Label == "" {
+			errs = append(errs, fmt.Errorf("%s: label is required", field))
+		}
+		if len(rule.Paths) == 0 {
+			errs = append(errs, fmt.Errorf("%s: at least one path is required", field))
+		}
+		for _, pattern := range rule.Paths {
+			if !doublestar.ValidatePattern(pattern) {
+				errs = append(errs, fmt.Errorf("%s: %q is not a valid glob", field, pattern))
+			}
+		}
+	}
+	for i, size := range p.SizeLabels.Labels {
+		field := fmt.Sprintf("%ssize_labels.labels[%d]", prefix, i)
+		if size.Label == "" {
+			errs = append(errs, fmt.Errorf("%s: label is required", field))
+		}
+		if size.Min < 0 {
+			errs = append(errs, fmt.Errorf("%s: min must not be negative", field))
+		}
+	}
+	for _, pattern := range p.SizeLabels.Exclude {
+		if !doublestar.ValidatePattern(pattern) {
+			errs = append(errs, fmt.Errorf("%ssize_labels.exclude: %q is not a valid glob", prefix, pattern))
+		}
+	}
+	if p.SizeLabels.OversizeThreshold < 0 {
+		errs = append(errs, fmt.Errorf("%ssize_labels.oversize_threshold must not be negative", prefix))
+	}
+	named := p.Templates.Named()
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if named[name] == "" {
+			continue
+		}
+		if err := templates.Validate(name, named[name]); err != nil {
+			errs = append(errs, fmt.Errorf("%stemplates: %w", prefix, err))
+		}
+	}
+	return errs
+}
+
+// isSlugPair reports whether s is two non-empty names separated by a single slash, e.g. owner/repo
+func isSlugPair(s string) bool {
+	first, second, ok := strings.Cut(s, "/")
+	return ok && first != "" && second != "" && !strings.Contains(second, "/") && !strings.ContainsAny(s, " \t")
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Approval is a fork workflow run the bot approved automatically
+type Approval struct {
+	Repo     string
+	PRNumber int64
+	RunID    int64
+	SHA      string
+	// Reason is why the PR's author was trusted
+	Reason     string
+	ApprovedAt time.Time
+}
+
+// ApprovalStore keeps a durable record of every workflow run the bot approved, since each one lets code from a fork run
+type ApprovalStore interface {
+	RecordApproval(approval Approval) error
+	Pinger
+	Close() error
+}
+
+// ApprovalDatastore stores workflow run approvals in a SQL table
+type ApprovalDatastore struct {
+	client    *sql.DB
+	dialect   dialect
+	tableName string
+}
+
+// NewApprovalStore connects using the driver from opts and ensures the given table exists
+func NewApprovalStore(opts Options, tableName string) (ApprovalStore, error) {
+	d, client, err := open(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = client.Exec(d.createApprovalTable(d.quote(tableName)))
+	if err != nil {
+		return nil, fmt.Errorf("error ensuring tables exist in %s: %w", d.name(), err)
+	}
+
+	return &ApprovalDatastore{
+		client:    client,
+		dialect:   d,
+		tableName: tableName,
+	}, nil
+}
+
+// RecordApproval stores a workflow run approval; ApprovedAt is set to the current time when it is zero
+func (d *ApprovalDatastore) RecordApproval(approval Approval) error {
+	approvedAt := approval.ApprovedAt
+	if approvedAt.IsZero() {
+		approvedAt = time.Now()
+	}
+	// The timestamp is set from Go rather than the database so every driver stores UTC
+	query := d.dialect.rebind(fmt.Sprintf("INSERT INTO %s (repo, pr_number, run_id, sha, reason, approved_at) VALUES (?, ?, ?, ?, ?, ?)", d.dialect.quote(d.tableName)))
+	_, err := d.client.Exec(query, approval.Repo, approval.PRNumber, approval.RunID, approval.SHA, approval.Reason, approvedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("error inserting workflow run approval: %v", err)
+	}
+	return nil
+}
+
+// Ping checks the database is still reachable
+func (d *ApprovalDatastore) Ping(ctx context.Context) error {
+	return d.client.PingContext(ctx)
+}
+
+// Close closes the underlying database connection
+func (d *ApprovalDatastore) Close() error {
+	return d.client.Close()
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApprovalStore(t *testing.T) {
+	store, err := NewApprovalStore(sqliteOptions(t), "auto_approvals")
+	if err != nil {
+		t.Fatalf("NewApprovalStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	approvedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	approvals := []Approval{
+		{Repo: "Chia-Network/chia-blockchain", PRNumber: 1, RunID: 100, SHA: "abc", Reason: "a member of the org", ApprovedAt: approvedAt},
+		{Repo: "Chia-Network/chia-blockchain", PRNumber: 1, RunID: 101, SHA: "abc", Reason: "a member of the org", ApprovedAt: approvedAt},
+		{Repo: "Chia-Network/other", PRNumber: 2, RunID: 200, SHA: "def", Reason: "has merged PRs"},
+	}
+	for _, approval := range approvals {
+		if err := store.RecordApproval(approval); err != nil {
+			t.Fatalf("RecordApproval: %v", err)
+		}
+	}
+
+	client := store.(*ApprovalDatastore).client
+	tests := []struct {
+		runID  int64
+		repo   string
+		pr     int64
+		sha    string
+		reason string
+	}{
+		{100, "Chia-Network/chia-blockchain", 1, "abc", "a member of the org"},
+		{101, "Chia-Network/chia-blockchain", 1, "abc", "a member of the org"},
+		{200, "Chia-Network/other", 2, "def", "has merged PRs"},
+	}
+	for _, test := range tests {
+		var repo, sha, reason string
+		var pr int64
+		var recordedAt time.Time
+		err := client.QueryRow("SELECT repo, pr_number, sha, reason, approved_at FROM auto_approvals WHERE run_id = ?", test.runID).Scan(&repo, &pr, &sha, &reason, &recordedAt)
+		if err != nil {
+			t.Fatalf("querying run %d: %v", test.runID, err)
+		}
+		if repo != test.repo || pr != test.pr || sha != test.sha || reason != test.reason {
+			t.Errorf("run %d = (%q, %d, %q, %q), want (%q, %d, %q, %q)", test.runID, repo, pr, sha, reason, test.repo, test.pr, test.sha, test.reason)
+		}
+		if recordedAt.IsZero() {
+			t.Errorf("run %d has no approved_at", test.runID)
+		}
+	}
+}
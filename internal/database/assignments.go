@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Assignment is a review the bot requested from a reviewer on a PR
+type Assignment struct {
+	Repo       string
+	PRNumber   int64
+	Reviewer   string
+	AssignedAt time.Time
+}
+
+// AssignmentStore records the reviews the bot requests, so reviews can be spread evenly between reviewers over time
+type AssignmentStore interface {
+	// PRAssignments returns the reviewers the bot already requested on the PR
+	PRAssignments(repo string, prNumber int64) ([]Assignment, error)
+	// AssignmentsSince returns every assignment made at or after since, in any repo
+	AssignmentsSince(since time.Time) ([]Assignment, error)
+	RecordAssignment(repo string, prNumber int64, reviewer string) error
+	Pinger
+	Close() error
+}
+
+// AssignmentDatastore stores review assignments in a SQL table
+type AssignmentDatastore struct {
+	client    *sql.DB
+	dialect   dialect
+	tableName string
+}
+
+// NewAssignmentStore connects using the driver from opts and ensures the given table exists
+func NewAssignmentStore(opts Options, tableName string) (AssignmentStore, error) {
+	d, client, err := open(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = client.Exec(d.createAssignmentTable(d.quote(tableName)))
+	if err != nil {
+		return nil, fmt.Errorf("error ensuring tables exist in %s: %w", d.name(), err)
+	}
+
+	return &AssignmentDatastore{
+		client:    client,
+		dialect:   d,
+		tableName: tableName,
+	}, nil
+}
+
+// PRAssignments returns the reviewers the bot already requested on the PR
+func (d *AssignmentDatastore) PRAssignments(repo string, prNumber int64) ([]Assignment, error) {
+	query := d.dialect.rebind(fmt.Sprintf("SELECT repo, pr_number, reviewer, assigned_at FROM %s WHERE repo = ? AND pr_number = ?", d.dialect.quote(d.tableName)))
+	return d.query(query, repo, prNumber)
+}
+
+// AssignmentsSince returns every assignment made at or after since, in any repo
+func (d *AssignmentDatastore) AssignmentsSince(since time.Time) ([]Assignment, error) {
+	query := d.dialect.rebind(fmt.Sprintf("SELECT repo, pr_number, reviewer, assigned_at FROM %s WHERE assigned_at >= ?", d.dialect.quote(d.tableName)))
+	return d.query(query, since.UTC())
+}
+
+func (d *AssignmentDatastore) query(query string, args ...any) ([]Assignment, error) {
+	rows, err := d.client.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying review assignments: %v", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var assignments []Assignment
+	for rows.Next() {
+		var assignment Assignment
+		err = rows.Scan(&assignment.Repo, &assignment.PRNumber, &assignment.Reviewer, &assignment.AssignedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error reading review assignment: %v", err)
+		}
+		assignments = append(assignments, assignment)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading review assignments: %v", err)
+	}
+	return assignments, nil
+}
+
+// RecordAssignment stores that the bot requested a review from reviewer on the PR
+func (d *AssignmentDatastore) RecordAssignment(repo string, prNumber int64, reviewer string) error {
+	// The timestamp is set from Go rather than the database so every driver stores UTC
+	query := d.dialect.rebind(fmt.Sprintf("INSERT INTO %s (repo, pr_number, reviewer, assigned_at) VALUES (?, ?, ?, ?)", d.dialect.quote(d.tableName)))
+	_, err := d.client.Exec(query, repo, prNumber, reviewer, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("error inserting review assignment: %v", err)
+	}
+	return nil
+}
+
+// Ping checks the database is still reachable
+func (d *AssignmentDatastore) Ping(ctx context.Context) error {
+	return d.client.PingContext(ctx)
+}
+
+// Close closes the underlying database connection
+func (d *AssignmentDatastore) Close() error {
+	return d.client.Close()
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAssignmentStore(t *testing.T) {
+	store, err := NewAssignmentStore(sqliteOptions(t), "review_assignments")
+	if err != nil {
+		t.Fatalf("NewAssignmentStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	start := time.Now().Add(-time.Second)
+	for _, assignment := range []Assignment{
+		{Repo: "repo", PRNumber: 1, Reviewer: "alice"},
+		{Repo: "repo", PRNumber: 1, Reviewer: "bob"},
+		{Repo: "other", PRNumber: 2, Reviewer: "alice"},
+	} {
+		if err := store.RecordAssignment(assignment.Repo, assignment.PRNumber, assignment.Reviewer); err != nil {
+			t.Fatalf("RecordAssignment: %v", err)
+		}
+	}
+
+	assignments, err := store.PRAssignments("repo", 1)
+	if err != nil {
+		t.Fatalf("PRAssignments: %v", err)
+	}
+	if len(assignments) != 2 {
+		t.Fatalf("got %d assignments on repo#1, want 2: %+v", len(assignments), assignments)
+	}
+	for _, assignment := range assignments {
+		if assignment.Repo != "repo" || assignment.PRNumber != 1 {
+			t.Errorf("unexpected assignment %+v", assignment)
+		}
+		if assignment.AssignedAt.Before(start) {
+			t.Errorf("assigned_at %s is before the test started", assignment.AssignedAt)
+		}
+	}
+
+	since, err := store.AssignmentsSince(start)
+	if err != nil {
+		t.Fatalf("AssignmentsSince: %v", err)
+	}
+	if len(since) != 3 {
+		t.Errorf("got %d assignments since the start, want 3", len(since))
+	}
+
+	later, err := store.AssignmentsSince(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("AssignmentsSince: %v", err)
+	}
+	if len(later) != 0 {
+		t.Errorf("got %d assignments in the future, want 0", len(later))
+	}
+}
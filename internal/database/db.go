@@ -1,12 +1,12 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
 
 	"github.com/chia-network/go-modules/pkg/slogs"
-	"github.com/go-sql-driver/mysql"
 )
 
 // PRInfo struct holds the PR information
@@ -15,81 +15,102 @@ type PRInfo struct {
 	PRNumber         int64
 	LastMessageSent  time.Time
 	SuppressMessages bool
+	// SnoozedUntil is zero when the PR is not snoozed
+	SnoozedUntil time.Time
+}
+
+// Pinger is implemented by every store so readiness checks can cover them
+type Pinger interface {
+	// Ping checks the database is still reachable
+	Ping(ctx context.Context) error
+}
+
+// Store persists per-PR notification state for a single job
+type Store interface {
+	// GetPRData returns nil without an error when the PR has no record yet
+	GetPRData(repo string, prNumber int64) (*PRInfo, error)
+	StorePRData(repo string, prNumber int64) error
+	UpdateSuppressMessages(repo string, prNumber int64, suppress bool) error
+	// SnoozeUntil holds back messages for the PR until the given time; a zero time clears the snooze
+	SnoozeUntil(repo string, prNumber int64, until time.Time) error
+	Pinger
+	Close() error
+}
+
+// Options selects the database driver and how to connect to it
+type Options struct {
+	// Driver is one of mysql, postgres or sqlite
+	Driver string
+	Host   string
+	// Port defaults to the driver's standard port when 0
+	Port uint16
+	User string
+	Pass string
+	Name string
+	// SSLMode is passed through to PostgreSQL
+	SSLMode string
+	// Path is the database file used by SQLite
+	Path string
 }
 
 // Datastore manages connections and the state of the database.
 type Datastore struct {
-	mysqlClient *sql.DB
-	dbHost      string
-	dbPort      uint16
-	dbUser      string
-	dbPass      string
-	dbName      string
-	tableName   string
+	client    *sql.DB
+	dialect   dialect
+	tableName string
 }
 
-// NewDatastore initializes a new Datastore with the given configurations.
-func NewDatastore(dbHost string, dbPort uint16, dbUser string, dbPass string, dbName string, tableName string) (*Datastore, error) {
+// NewDatastore connects using the driver from opts and ensures the given table exists
+func NewDatastore(opts Options, tableName string) (Store, error) {
+	d, client, err := open(opts)
+	if err != nil {
+		return nil, err
+	}
 
 	datastore := &Datastore{
-		dbHost:    dbHost,
-		dbPort:    dbPort,
-		dbUser:    dbUser,
-		dbPass:    dbPass,
-		dbName:    dbName,
+		client:    client,
+		dialect:   d,
 		tableName: tableName,
 	}
-
-	err := datastore.createDBClient()
-	if err != nil {
-		return nil, fmt.Errorf("error creating mysql client: %w", err)
-	}
 	err = datastore.initTables()
 	if err != nil {
-		return nil, fmt.Errorf("error ensuring tables exist in MySQL: %w", err)
+		return nil, fmt.Errorf("error ensuring tables exist in %s: %w", d.name(), err)
 	}
 
 	return datastore, nil
 }
 
-// createDBClient sets up the database connection.
-func (d *Datastore) createDBClient() error {
-	var err error
-	cfg := mysql.Config{
-		User:                 d.dbUser,
-		Passwd:               d.dbPass,
-		Net:                  "tcp",
-		Addr:                 fmt.Sprintf("%s:%d", d.dbHost, d.dbPort),
-		DBName:               d.dbName,
-		AllowNativePasswords: true,
-	}
-	d.mysqlClient, err = sql.Open("mysql", cfg.FormatDSN())
-	if err != nil {
-		return err
+// open connects to the database selected by opts.Driver
+func open(opts Options) (dialect, *sql.DB, error) {
+	var d dialect
+	switch opts.Driver {
+	case "", "mysql":
+		d = mysqlDialect{}
+	case "postgres":
+		d = postgresDialect{}
+	case "sqlite":
+		d = sqliteDialect{}
+	default:
+		return nil, nil, fmt.Errorf("unknown database driver %q", opts.Driver)
 	}
 
-	d.mysqlClient.SetConnMaxLifetime(time.Second * 15)
-	d.mysqlClient.SetMaxOpenConns(1)
-	d.mysqlClient.SetMaxIdleConns(1)
-
-	return nil
+	client, err := d.open(opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating %s client: %w", d.name(), err)
+	}
+	return d, client, nil
 }
 
 func (d *Datastore) initTables() error {
-	if d.mysqlClient == nil {
-		return fmt.Errorf("mysqlClient not initialized")
-	}
-	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%s` ("+
-		"  `id` bigint unsigned NOT NULL AUTO_INCREMENT,"+
-		"  `repo` VARCHAR(255) NOT NULL,"+
-		"  `pr_number` bigint NOT NULL,"+
-		"  `last_message_sent` DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,"+
-		"  `suppress_messages` BOOLEAN NOT NULL DEFAULT FALSE,"+
-		"  PRIMARY KEY (`id`),"+
-		"  UNIQUE KEY `repo_pr_number_unique` (`repo`, `pr_number`)"+
-		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;", d.tableName)
-
-	_, err := d.mysqlClient.Exec(query)
+	if d.client == nil {
+		return fmt.Errorf("database client not initialized")
+	}
+
+	_, err := d.client.Exec(d.dialect.createPRTable(d.tableName))
+	if err != nil {
+		return err
+	}
+	err = d.dialect.migratePRTable(d.client, d.tableName)
 	if err != nil {
 		return err
 	}
@@ -97,27 +118,26 @@ func (d *Datastore) initTables() error {
 	// List of required columns
 	requiredColumns := map[string]string{
 		"suppress_messages": "BOOLEAN NOT NULL DEFAULT FALSE",
+		"snoozed_until":     d.dialect.timestampType(),
 		// Add other columns here as needed
 	}
 
 	// Check for missing columns and add them if necessary
 	for column, definition := range requiredColumns {
-		var columnName string
-		query := fmt.Sprintf("SHOW COLUMNS FROM `%s` LIKE '%s'", d.tableName, column)
-		err := d.mysqlClient.QueryRow(query).Scan(&columnName, new(string), new(string), new(string), new(string), new(string))
+		exists, err := d.dialect.hasColumn(d.client, d.tableName, column)
 		if err != nil {
-			if err == sql.ErrNoRows {
-				// Column does not exist, add it
-				alterQuery := fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` %s", d.tableName, column, definition)
-				_, err := d.mysqlClient.Exec(alterQuery)
-				if err != nil {
-					return fmt.Errorf("error adding column %s: %v", column, err)
-				}
-				slogs.Logr.Info("Added column to table", "table", d.tableName, "column", column)
-			} else {
-				return fmt.Errorf("error checking column %s: %v", column, err)
-			}
+			return fmt.Errorf("error checking column %s: %v", column, err)
 		}
+		if exists {
+			continue
+		}
+
+		alterQuery := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", d.dialect.quote(d.tableName), d.dialect.quote(column), definition)
+		_, err = d.client.Exec(alterQuery)
+		if err != nil {
+			return fmt.Errorf("error adding column %s: %v", column, err)
+		}
+		slogs.Logr.Info("Added column to table", "table", d.tableName, "column", column)
 	}
 
 	return nil
@@ -125,39 +145,29 @@ func (d *Datastore) initTables() error {
 
 // GetPRData retrieves PR information from the database.
 func (d *Datastore) GetPRData(repo string, prNumber int64) (*PRInfo, error) {
-	// Prepare the query to fetch the PR information
-	query := fmt.Sprintf("SELECT repo, pr_number, last_message_sent FROM %s WHERE repo = ? AND pr_number = ?", d.tableName)
+	query := d.dialect.rebind(fmt.Sprintf("SELECT repo, pr_number, last_message_sent, suppress_messages, snoozed_until FROM %s WHERE repo = ? AND pr_number = ?", d.dialect.quote(d.tableName)))
 
-	// Variable to store the results
 	var prInfo PRInfo
-	var lastMessageSentStr string
+	var lastMessageSent, snoozedUntil sql.NullTime
 
-	// Execute the query
-	err := d.mysqlClient.QueryRow(query, repo, prNumber).Scan(&prInfo.Repo, &prInfo.PRNumber, &lastMessageSentStr)
+	err := d.client.QueryRow(query, repo, prNumber).Scan(&prInfo.Repo, &prInfo.PRNumber, &lastMessageSent, &prInfo.SuppressMessages, &snoozedUntil)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			// Handle no rows returned case here if needed
 			return nil, nil // No data found is not an error in this context
 		}
-		// Handle other errors
 		return nil, fmt.Errorf("error querying PR info: %v", err)
 	}
+	prInfo.LastMessageSent = lastMessageSent.Time
+	prInfo.SnoozedUntil = snoozedUntil.Time
 
-	// Parse the last_message_sent string to time.Time. Reference date is used here.
-	lastMessageSent, err := time.Parse("2006-01-02 15:04:05", lastMessageSentStr)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing last_message_sent: %v", err)
-	}
-	prInfo.LastMessageSent = lastMessageSent
-
-	// Return the fetched data
 	return &prInfo, nil
 }
 
 // StorePRData stores or updates PR information in the database.
 func (d *Datastore) StorePRData(repo string, prNumber int64) error {
-	query := fmt.Sprintf("INSERT INTO %s (repo, pr_number, last_message_sent) VALUES (?, ?, NOW()) ON DUPLICATE KEY UPDATE last_message_sent = VALUES(last_message_sent);", d.tableName)
-	_, err := d.mysqlClient.Exec(query, repo, prNumber)
+	query := d.dialect.rebind(fmt.Sprintf("INSERT INTO %s (repo, pr_number, last_message_sent) VALUES (?, ?, %s) %s",
+		d.dialect.quote(d.tableName), d.dialect.now(), d.dialect.onConflict("last_message_sent")))
+	_, err := d.client.Exec(query, repo, prNumber)
 	if err != nil {
 		return fmt.Errorf("error inserting or updating PR status: %v", err)
 	}
@@ -165,10 +175,10 @@ func (d *Datastore) StorePRData(repo string, prNumber int64) error {
 	return nil
 }
 
-// UpdateSuppressMessages updates the suppress_messages flag for a PR.
+// UpdateSuppressMessages updates the suppress_messages flag for a PR, creating its record if it has none yet so PRs can be
+// suppressed before their first message. last_message_sent is left as it is.
 func (d *Datastore) UpdateSuppressMessages(repo string, prNumber int64, suppress bool) error {
-	query := fmt.Sprintf("UPDATE %s SET suppress_messages = ? WHERE repo = ? AND pr_number = ?", d.tableName)
-	_, err := d.mysqlClient.Exec(query, suppress, repo, prNumber)
+	err := d.setColumn(repo, prNumber, "suppress_messages", suppress)
 	if err != nil {
 		return fmt.Errorf("error updating suppress_messages: %v", err)
 	}
@@ -181,3 +191,36 @@ func (d *Datastore) UpdateSuppressMessages(repo string, prNumber int64, suppress
 
 	return nil
 }
+
+// SnoozeUntil sets or clears snoozed_until for a PR, creating its record if it has none yet. last_message_sent is left as it is.
+func (d *Datastore) SnoozeUntil(repo string, prNumber int64, until time.Time) error {
+	err := d.setColumn(repo, prNumber, "snoozed_until", sql.NullTime{Time: until.UTC(), Valid: !until.IsZero()})
+	if err != nil {
+		return fmt.Errorf("error updating snoozed_until: %v", err)
+	}
+
+	if until.IsZero() {
+		slogs.Logr.Info("Snooze cleared for PR", "repository", repo, "PR", prNumber)
+	} else {
+		slogs.Logr.Info("PR snoozed", "repository", repo, "PR", prNumber, "until", until.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// setColumn sets one column of a PR's row. A row it creates has no last_message_sent, so the PR's first message isn't held back.
+func (d *Datastore) setColumn(repo string, prNumber int64, column string, value any) error {
+	query := d.dialect.rebind(fmt.Sprintf("INSERT INTO %s (repo, pr_number, last_message_sent, %s) VALUES (?, ?, NULL, ?) %s",
+		d.dialect.quote(d.tableName), column, d.dialect.onConflict(column)))
+	_, err := d.client.Exec(query, repo, prNumber, value)
+	return err
+}
+
+// Ping checks the database is still reachable
+func (d *Datastore) Ping(ctx context.Context) error {
+	return d.client.PingContext(ctx)
+}
+
+// Close closes the underlying database connection
+func (d *Datastore) Close() error {
+	return d.client.Close()
+}
@@ -0,0 +1,169 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// sqliteOptions returns options for a fresh SQLite database in the test's temporary directory
+func sqliteOptions(t *testing.T) Options {
+	t.Helper()
+	return Options{Driver: "sqlite", Path: filepath.Join(t.TempDir(), "github-bot.db")}
+}
+
+func newTestDatastore(t *testing.T, opts Options) Store {
+	t.Helper()
+	store, err := NewDatastore(opts, "stale_pr_status")
+	if err != nil {
+		t.Fatalf("NewDatastore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func getPRData(t *testing.T, store Store, repo string, prNumber int64) *PRInfo {
+	t.Helper()
+	info, err := store.GetPRData(repo, prNumber)
+	if err != nil {
+		t.Fatalf("GetPRData: %v", err)
+	}
+	return info
+}
+
+func TestNewDatastoreUnknownDriver(t *testing.T) {
+	_, err := NewDatastore(Options{Driver: "oracle"}, "stale_pr_status")
+	if err == nil {
+		t.Fatal("expected an error for an unknown driver")
+	}
+}
+
+func TestNewDatastoreSQLiteRequiresPath(t *testing.T) {
+	_, err := NewDatastore(Options{Driver: "sqlite"}, "stale_pr_status")
+	if err == nil {
+		t.Fatal("expected an error without a database path")
+	}
+}
+
+func TestGetPRDataMissing(t *testing.T) {
+	store := newTestDatastore(t, sqliteOptions(t))
+	if info := getPRData(t, store, "repo", 1); info != nil {
+		t.Fatalf("expected no record, got %+v", info)
+	}
+}
+
+func TestStorePRData(t *testing.T) {
+	store := newTestDatastore(t, sqliteOptions(t))
+
+	before := time.Now().Add(-time.Second)
+	if err := store.StorePRData("repo", 1); err != nil {
+		t.Fatalf("StorePRData: %v", err)
+	}
+	info := getPRData(t, store, "repo", 1)
+	if info == nil {
+		t.Fatal("expected a record after StorePRData")
+	}
+	if info.Repo != "repo" || info.PRNumber != 1 {
+		t.Errorf("got %s#%d, want repo#1", info.Repo, info.PRNumber)
+	}
+	if info.LastMessageSent.Before(before.Truncate(time.Second)) || info.LastMessageSent.After(time.Now().Add(time.Second)) {
+		t.Errorf("last_message_sent %s is not the current time", info.LastMessageSent)
+	}
+	if info.SuppressMessages || !info.SnoozedUntil.IsZero() {
+		t.Errorf("new record should not be suppressed or snoozed: %+v", info)
+	}
+
+	// A second call updates the same row rather than failing on the unique key
+	if err := store.StorePRData("repo", 1); err != nil {
+		t.Fatalf("StorePRData again: %v", err)
+	}
+	if other := getPRData(t, store, "repo", 2); other != nil {
+		t.Errorf("PR 2 should have no record, got %+v", other)
+	}
+}
+
+func TestUpdateSuppressMessages(t *testing.T) {
+	store := newTestDatastore(t, sqliteOptions(t))
+
+	// Suppressing a PR with no record creates one, without marking it as notified
+	if err := store.UpdateSuppressMessages("repo", 1, true); err != nil {
+		t.Fatalf("UpdateSuppressMessages: %v", err)
+	}
+	info := getPRData(t, store, "repo", 1)
+	if info == nil || !info.SuppressMessages {
+		t.Fatalf("expected a suppressed record, got %+v", info)
+	}
+	if !info.LastMessageSent.IsZero() {
+		t.Errorf("suppressing should not set last_message_sent, got %s", info.LastMessageSent)
+	}
+
+	if err := store.StorePRData("repo", 1); err != nil {
+		t.Fatalf("StorePRData: %v", err)
+	}
+	sent := getPRData(t, store, "repo", 1).LastMessageSent
+
+	// Unsuppressing keeps last_message_sent, so the re-notify interval is unaffected
+	if err := store.UpdateSuppressMessages("repo", 1, false); err != nil {
+		t.Fatalf("UpdateSuppressMessages: %v", err)
+	}
+	info = getPRData(t, store, "repo", 1)
+	if info.SuppressMessages {
+		t.Error("expected messages to be unsuppressed")
+	}
+	if !info.LastMessageSent.Equal(sent) {
+		t.Errorf("last_message_sent changed from %s to %s", sent, info.LastMessageSent)
+	}
+}
+
+func TestSnoozeUntil(t *testing.T) {
+	store := newTestDatastore(t, sqliteOptions(t))
+	if err := store.StorePRData("repo", 1); err != nil {
+		t.Fatalf("StorePRData: %v", err)
+	}
+	sent := getPRData(t, store, "repo", 1).LastMessageSent
+
+	until := time.Date(2030, 1, 2, 3, 4, 5, 0, time.FixedZone("UTC+2", 2*60*60))
+	if err := store.SnoozeUntil("repo", 1, until); err != nil {
+		t.Fatalf("SnoozeUntil: %v", err)
+	}
+	info := getPRData(t, store, "repo", 1)
+	if !info.SnoozedUntil.Equal(until) {
+		t.Errorf("snoozed_until is %s, want %s", info.SnoozedUntil, until)
+	}
+	if !info.LastMessageSent.Equal(sent) {
+		t.Errorf("snoozing changed last_message_sent from %s to %s", sent, info.LastMessageSent)
+	}
+
+	if err := store.SnoozeUntil("repo", 1, time.Time{}); err != nil {
+		t.Fatalf("SnoozeUntil zero: %v", err)
+	}
+	if info := getPRData(t, store, "repo", 1); !info.SnoozedUntil.IsZero() {
+		t.Errorf("expected the snooze to be cleared, got %s", info.SnoozedUntil)
+	}
+
+	// Snoozing a PR with no record creates one
+	if err := store.SnoozeUntil("repo", 2, until); err != nil {
+		t.Fatalf("SnoozeUntil: %v", err)
+	}
+	info = getPRData(t, store, "repo", 2)
+	if info == nil || !info.SnoozedUntil.Equal(until) || !info.LastMessageSent.IsZero() {
+		t.Errorf("expected a snoozed record that was never notified, got %+v", info)
+	}
+}
+
+func TestDatastoreReopen(t *testing.T) {
+	opts := sqliteOptions(t)
+	store := newTestDatastore(t, opts)
+	if err := store.UpdateSuppressMessages("repo", 1, true); err != nil {
+		t.Fatalf("UpdateSuppressMessages: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Opening an existing table must not fail on the columns it already has
+	reopened := newTestDatastore(t, opts)
+	if info := getPRData(t, reopened, "repo", 1); info == nil || !info.SuppressMessages {
+		t.Errorf("expected the record to survive reopening, got %+v", info)
+	}
+}
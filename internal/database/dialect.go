@@ -0,0 +1,45 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// dialect holds everything that differs between the supported SQL databases
+type dialect interface {
+	name() string
+	open(opts Options) (*sql.DB, error)
+	// quote quotes a table or column name
+	quote(identifier string) string
+	// rebind rewrites ? placeholders into the driver's placeholder syntax
+	rebind(query string) string
+	createPRTable(quotedTable string) string
+	createAssignmentTable(quotedTable string) string
+	createWelcomeTable(quotedTable string) string
+	createApprovalTable(quotedTable string) string
+	hasColumn(client *sql.DB, table string, column string) (bool, error)
+	// timestampType is the column type used for nullable timestamps
+	timestampType() string
+	// now is the SQL expression last_message_sent is set to
+	now() string
+	// onConflict is the clause that turns an insert into the PR table into an update of column when the PR already has a row
+	onConflict(column string) string
+	// migratePRTable brings a PR table created by an earlier version up to date
+	migratePRTable(client *sql.DB, table string) error
+}
+
+// rebindNumbered replaces each ? with $1, $2, ... as used by PostgreSQL
+func rebindNumbered(query string) string {
+	var builder strings.Builder
+	n := 0
+	for _, char := range query {
+		if char == '?' {
+			n++
+			builder.WriteString(fmt.Sprintf("$%d", n))
+			continue
+		}
+		builder.WriteRune(char)
+	}
+	return builder.String()
+}
@@ -0,0 +1,52 @@
+package database
+
+import "testing"
+
+func TestRebindNumbered(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"SELECT 1", "SELECT 1"},
+		{"SELECT * FROM t WHERE repo = ?", "SELECT * FROM t WHERE repo = $1"},
+		{"INSERT INTO t (a, b, c) VALUES (?, ?, ?)", "INSERT INTO t (a, b, c) VALUES ($1, $2, $3)"},
+	}
+	for _, test := range tests {
+		if got := rebindNumbered(test.query); got != test.want {
+			t.Errorf("rebindNumbered(%q) = %q, want %q", test.query, got, test.want)
+		}
+	}
+}
+
+func TestDialectPlaceholders(t *testing.T) {
+	query := "SELECT * FROM t WHERE repo = ? AND pr_number = ?"
+	tests := []struct {
+		dialect dialect
+		want    string
+	}{
+		{mysqlDialect{}, query},
+		{sqliteDialect{}, query},
+		{postgresDialect{}, "SELECT * FROM t WHERE repo = $1 AND pr_number = $2"},
+	}
+	for _, test := range tests {
+		if got := test.dialect.rebind(query); got != test.want {
+			t.Errorf("%s rebind = %q, want %q", test.dialect.name(), got, test.want)
+		}
+	}
+}
+
+func TestDialectQuote(t *testing.T) {
+	tests := []struct {
+		dialect dialect
+		want    string
+	}{
+		{mysqlDialect{}, "`stale_pr_status`"},
+		{sqliteDialect{}, `"stale_pr_status"`},
+		{postgresDialect{}, `"stale_pr_status"`},
+	}
+	for _, test := range tests {
+		if got := test.dialect.quote("stale_pr_status"); got != test.want {
+			t.Errorf("%s quote = %q, want %q", test.dialect.name(), got, test.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/go-sql-driver/mysql"
+)
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) name() string {
+	return "MySQL"
+}
+
+func (mysqlDialect) open(opts Options) (*sql.DB, error) {
+	port := opts.Port
+	if port == 0 {
+		port = 3306
+	}
+	cfg := mysql.Config{
+		User:                 opts.User,
+		Passwd:               opts.Pass,
+		Net:                  "tcp",
+		Addr:                 fmt.Sprintf("%s:%d", opts.Host, port),
+		DBName:               opts.Name,
+		AllowNativePasswords: true,
+		ParseTime:            true,
+	}
+	client, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		return nil, err
+	}
+
+	client.SetConnMaxLifetime(time.Second * 15)
+	client.SetMaxOpenConns(1)
+	client.SetMaxIdleConns(1)
+
+	return client, nil
+}
+
+func (mysqlDialect) quote(identifier string) string {
+	return fmt.Sprintf("`%s`", identifier)
+}
+
+func (mysqlDialect) rebind(query string) string {
+	return query
+}
+
+func (mysqlDialect) createPRTable(quotedTable string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s ("+
+		"  `id` bigint unsigned NOT NULL AUTO_INCREMENT,"+
+		"  `repo` VARCHAR(255) NOT NULL,"+
+		"  `pr_number` bigint NOT NULL,"+
+		"  `last_message_sent` DATETIME NULL,"+
+		"  `suppress_messages` BOOLEAN NOT NULL DEFAULT FALSE,"+
+		"  PRIMARY KEY (`id`),"+
+		"  UNIQUE KEY `repo_pr_number_unique` (`repo`, `pr_number`)"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;", quotedTable)
+}
+
+func (mysqlDialect) createAssignmentTable(quotedTable string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s ("+
+		"  `id` bigint unsigned NOT NULL AUTO_INCREMENT,"+
+		"  `repo` VARCHAR(255) NOT NULL,"+
+		"  `pr_number` bigint NOT NULL,"+
+		"  `reviewer` VARCHAR(255) NOT NULL,"+
+		"  `assigned_at` DATETIME NOT NULL,"+
+		"  PRIMARY KEY (`id`),"+
+		"  UNIQUE KEY `repo_pr_number_reviewer_unique` (`repo`, `pr_number`, `reviewer`),"+
+		"  KEY `assigned_at_index` (`assigned_at`)"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;", quotedTable)
+}
+
+func (mysqlDialect) createWelcomeTable(quotedTable string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s ("+
+		"  `id` bigint unsigned NOT NULL AUTO_INCREMENT,"+
+		"  `repo` VARCHAR(255) NOT NULL,"+
+		"  `author` VARCHAR(255) NOT NULL,"+
+		"  `pr_number` bigint NOT NULL,"+
+		"  `welcomed_at` DATETIME NOT NULL,"+
+		"  PRIMARY KEY (`id`),"+
+		"  UNIQUE KEY `repo_author_unique` (`repo`, `author`)"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;", quotedTable)
+}
+
+func (mysqlDialect) createApprovalTable(quotedTable string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s ("+
+		"  `id` bigint unsigned NOT NULL AUTO_INCREMENT,"+
+		"  `repo` VARCHAR(255) NOT NULL,"+
+		"  `pr_number` bigint NOT NULL,"+
+		"  `run_id` bigint NOT NULL,"+
+		"  `sha` VARCHAR(64) NOT NULL,"+
+		"  `reason` VARCHAR(255) NOT NULL,"+
+		"  `approved_at` DATETIME NOT NULL,"+
+		"  PRIMARY KEY (`id`),"+
+		"  KEY `repo_pr_number_index` (`repo`, `pr_number`)"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;", quotedTable)
+}
+
+func (mysqlDialect) hasColumn(client *sql.DB, table string, column string) (bool, error) {
+	var columnName string
+	query := fmt.Sprintf("SHOW COLUMNS FROM `%s` LIKE '%s'", table, column)
+	err := client.QueryRow(query).Scan(&columnName, new(string), new(string), new(string), new(sql.NullString), new(string))
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (mysqlDialect) timestampType() string {
+	return "DATETIME NULL"
+}
+
+// now keeps using the server's clock, as rows written before other databases were supported did, so the column has one timezone
+func (mysqlDialect) now() string {
+	return "NOW()"
+}
+
+func (mysqlDialect) onConflict(column string) string {
+	return fmt.Sprintf("ON DUPLICATE KEY UPDATE %s = VALUES(%s)", column, column)
+}
+
+// migratePRTable drops ON UPDATE CURRENT_TIMESTAMP from last_message_sent, which earlier versions created it with.
+// Otherwise suppressing or snoozing a PR would also reset when it was last notified.
+func (mysqlDialect) migratePRTable(client *sql.DB, table string) error {
+	var extra string
+	err := client.QueryRow("SELECT EXTRA FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = 'last_message_sent'", table).Scan(&extra)
+	if err != nil {
+		return fmt.Errorf("error checking last_message_sent: %w", err)
+	}
+	if !strings.Contains(strings.ToLower(extra), "on update") {
+		return nil
+	}
+	_, err = client.Exec(fmt.Sprintf("ALTER TABLE `%s` MODIFY `last_message_sent` DATETIME NULL", table))
+	if err != nil {
+		return fmt.Errorf("error removing ON UPDATE from last_message_sent: %w", err)
+	}
+	slogs.Logr.Info("Removed ON UPDATE from last_message_sent", "table", table)
+	return nil
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"time"
+
+	_ "github.com/lib/pq" // Registers the postgres driver
+)
+
+type postgresDialect struct{}
+
+func (postgresDialect) name() string {
+	return "PostgreSQL"
+}
+
+func (postgresDialect) open(opts Options) (*sql.DB, error) {
+	port := opts.Port
+	if port == 0 {
+		port = 5432
+	}
+	sslMode := opts.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(opts.User, opts.Pass),
+		Host:     fmt.Sprintf("%s:%d", opts.Host, port),
+		Path:     opts.Name,
+		RawQuery: url.Values{"sslmode": []string{sslMode}}.Encode(),
+	}
+	client, err := sql.Open("postgres", dsn.String())
+	if err != nil {
+		return nil, err
+	}
+
+	client.SetConnMaxLifetime(time.Second * 15)
+	client.SetMaxOpenConns(1)
+	client.SetMaxIdleConns(1)
+
+	return client, nil
+}
+
+func (postgresDialect) quote(identifier string) string {
+	return fmt.Sprintf(`"%s"`, identifier)
+}
+
+func (postgresDialect) rebind(query string) string {
+	return rebindNumbered(query)
+}
+
+func (postgresDialect) createPRTable(quotedTable string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s ("+
+		"  id BIGSERIAL PRIMARY KEY,"+
+		"  repo VARCHAR(255) NOT NULL,"+
+		"  pr_number BIGINT NOT NULL,"+
+		"  last_message_sent TIMESTAMP NULL,"+
+		"  suppress_messages BOOLEAN NOT NULL DEFAULT FALSE,"+
+		"  UNIQUE (repo, pr_number)"+
+		")", quotedTable)
+}
+
+func (postgresDialect) createAssignmentTable(quotedTable string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s ("+
+		"  id BIGSERIAL PRIMARY KEY,"+
+		"  repo VARCHAR(255) NOT NULL,"+
+		"  pr_number BIGINT NOT NULL,"+
+		"  reviewer VARCHAR(255) NOT NULL,"+
+		"  assigned_at TIMESTAMP NOT NULL,"+
+		"  UNIQUE (repo, pr_number, reviewer)"+
+		")", quotedTable)
+}
+
+func (postgresDialect) createWelcomeTable(quotedTable string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s ("+
+		"  id BIGSERIAL PRIMARY KEY,"+
+		"  repo VARCHAR(255) NOT NULL,"+
+		"  author VARCHAR(255) NOT NULL,"+
+		"  pr_number BIGINT NOT NULL,"+
+		"  welcomed_at TIMESTAMP NOT NULL,"+
+		"  UNIQUE (repo, author)"+
+		")", quotedTable)
+}
+
+func (postgresDialect) createApprovalTable(quotedTable string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s ("+
+		"  id BIGSERIAL PRIMARY KEY,"+
+		"  repo VARCHAR(255) NOT NULL,"+
+		"  pr_number BIGINT NOT NULL,"+
+		"  run_id BIGINT NOT NULL,"+
+		"  sha VARCHAR(64) NOT NULL,"+
+		"  reason VARCHAR(255) NOT NULL,"+
+		"  approved_at TIMESTAMP NOT NULL"+
+		")", quotedTable)
+}
+
+func (postgresDialect) hasColumn(client *sql.DB, table string, column string) (bool, error) {
+	var count int
+	err := client.QueryRow("SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $1 AND column_name = $2", table, column).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (postgresDialect) timestampType() string {
+	return "TIMESTAMP NULL"
+}
+
+// now is in UTC, since last_message_sent has no timezone and is read back as UTC
+func (postgresDialect) now() string {
+	return "(CURRENT_TIMESTAMP AT TIME ZONE 'UTC')"
+}
+
+func (postgresDialect) onConflict(column string) string {
+	return fmt.Sprintf("ON CONFLICT (repo, pr_number) DO UPDATE SET %s = excluded.%s", column, column)
+}
+
+func (postgresDialect) migratePRTable(client *sql.DB, table string) error {
+	return nil
+}
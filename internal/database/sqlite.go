@@ -0,0 +1,109 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "modernc.org/sqlite" // Registers the pure Go sqlite driver, so static CGO_ENABLED=0 builds support it
+)
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) name() string {
+	return "SQLite"
+}
+
+func (sqliteDialect) open(opts Options) (*sql.DB, error) {
+	path := opts.Path
+	if path == "" {
+		return nil, fmt.Errorf("a database path is required for sqlite")
+	}
+	client, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", path))
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite only allows one writer at a time
+	client.SetMaxOpenConns(1)
+
+	return client, nil
+}
+
+func (sqliteDialect) quote(identifier string) string {
+	return fmt.Sprintf(`"%s"`, identifier)
+}
+
+func (sqliteDialect) rebind(query string) string {
+	return query
+}
+
+func (sqliteDialect) createPRTable(quotedTable string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s ("+
+		"  id INTEGER PRIMARY KEY AUTOINCREMENT,"+
+		"  repo TEXT NOT NULL,"+
+		"  pr_number INTEGER NOT NULL,"+
+		"  last_message_sent DATETIME NULL,"+
+		"  suppress_messages BOOLEAN NOT NULL DEFAULT FALSE,"+
+		"  UNIQUE (repo, pr_number)"+
+		")", quotedTable)
+}
+
+func (sqliteDialect) createAssignmentTable(quotedTable string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s ("+
+		"  id INTEGER PRIMARY KEY AUTOINCREMENT,"+
+		"  repo TEXT NOT NULL,"+
+		"  pr_number INTEGER NOT NULL,"+
+		"  reviewer TEXT NOT NULL,"+
+		"  assigned_at DATETIME NOT NULL,"+
+		"  UNIQUE (repo, pr_number, reviewer)"+
+		")", quotedTable)
+}
+
+func (sqliteDialect) createWelcomeTable(quotedTable string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s ("+
+		"  id INTEGER PRIMARY KEY AUTOINCREMENT,"+
+		"  repo TEXT NOT NULL,"+
+		"  author TEXT NOT NULL,"+
+		"  pr_number INTEGER NOT NULL,"+
+		"  welcomed_at DATETIME NOT NULL,"+
+		"  UNIQUE (repo, author)"+
+		")", quotedTable)
+}
+
+func (sqliteDialect) createApprovalTable(quotedTable string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s ("+
+		"  id INTEGER PRIMARY KEY AUTOINCREMENT,"+
+		"  repo TEXT NOT NULL,"+
+		"  pr_number INTEGER NOT NULL,"+
+		"  run_id INTEGER NOT NULL,"+
+		"  sha TEXT NOT NULL,"+
+		"  reason TEXT NOT NULL,"+
+		"  approved_at DATETIME NOT NULL"+
+		")", quotedTable)
+}
+
+func (sqliteDialect) hasColumn(client *sql.DB, table string, column string) (bool, error) {
+	var count int
+	err := client.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (sqliteDialect) timestampType() string {
+	return "DATETIME NULL"
+}
+
+// now is in UTC, as SQLite's CURRENT_TIMESTAMP always is
+func (sqliteDialect) now() string {
+	return "CURRENT_TIMESTAMP"
+}
+
+func (sqliteDialect) onConflict(column string) string {
+	return fmt.Sprintf("ON CONFLICT (repo, pr_number) DO UPDATE SET %s = excluded.%s", column, column)
+}
+
+func (sqliteDialect) migratePRTable(client *sql.DB, table string) error {
+	return nil
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// WelcomeStore records which community authors were welcomed to which repos, so each is only welcomed once
+type WelcomeStore interface {
+	Welcomed(repo string, author string) (bool, error)
+	RecordWelcome(repo string, author string, prNumber int64) error
+	Pinger
+	Close() error
+}
+
+// WelcomeDatastore stores welcomed authors in a SQL table
+type WelcomeDatastore struct {
+	client    *sql.DB
+	dialect   dialect
+	tableName string
+}
+
+// NewWelcomeStore connects using the driver from opts and ensures the given table exists
+func NewWelcomeStore(opts Options, tableName string) (WelcomeStore, error) {
+	d, client, err := open(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = client.Exec(d.createWelcomeTable(d.quote(tableName)))
+	if err != nil {
+		return nil, fmt.Errorf("error ensuring tables exist in %s: %w", d.name(), err)
+	}
+
+	return &WelcomeDatastore{
+		client:    client,
+		dialect:   d,
+		tableName: tableName,
+	}, nil
+}
+
+// Welcomed reports whether the author was already welcomed to the repo
+func (d *WelcomeDatastore) Welcomed(repo string, author string) (bool, error) {
+	query := d.dialect.rebind(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE repo = ? AND author = ?", d.dialect.quote(d.tableName)))
+	var count int
+	err := d.client.QueryRow(query, repo, author).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("error querying welcomed authors: %v", err)
+	}
+	return count > 0, nil
+}
+
+// RecordWelcome stores that the author was welcomed to the repo on the given PR
+func (d *WelcomeDatastore) RecordWelcome(repo string, author string, prNumber int64) error {
+	// The timestamp is set from Go rather than the database so every driver stores UTC
+	query := d.dialect.rebind(fmt.Sprintf("INSERT INTO %s (repo, author, pr_number, welcomed_at) VALUES (?, ?, ?, ?)", d.dialect.quote(d.tableName)))
+	_, err := d.client.Exec(query, repo, author, prNumber, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("error inserting welcomed author: %v", err)
+	}
+	return nil
+}
+
+// Ping checks the database is still reachable
+func (d *WelcomeDatastore) Ping(ctx context.Context) error {
+	return d.client.PingContext(ctx)
+}
+
+// Close closes the underlying database connection
+func (d *WelcomeDatastore) Close() error {
+	return d.client.Close()
+}
@@ -0,0 +1,42 @@
+package database
+
+import "testing"
+
+func TestWelcomeStore(t *testing.T) {
+	store, err := NewWelcomeStore(sqliteOptions(t), "welcomed_authors")
+	if err != nil {
+		t.Fatalf("NewWelcomeStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	welcomed, err := store.Welcomed("repo", "alice")
+	if err != nil {
+		t.Fatalf("Welcomed: %v", err)
+	}
+	if welcomed {
+		t.Fatal("alice should not be welcomed yet")
+	}
+
+	if err := store.RecordWelcome("repo", "alice", 1); err != nil {
+		t.Fatalf("RecordWelcome: %v", err)
+	}
+
+	tests := []struct {
+		repo   string
+		author string
+		want   bool
+	}{
+		{"repo", "alice", true},
+		{"repo", "bob", false},
+		{"other", "alice", false},
+	}
+	for _, test := range tests {
+		welcomed, err := store.Welcomed(test.repo, test.author)
+		if err != nil {
+			t.Fatalf("Welcomed: %v", err)
+		}
+		if welcomed != test.want {
+			t.Errorf("Welcomed(%q, %q) = %t, want %t", test.repo, test.author, welcomed, test.want)
+		}
+	}
+}
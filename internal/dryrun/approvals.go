@@ -0,0 +1,37 @@
+package dryrun
+
+import (
+	"context"
+
+	"github.com/chia-network/github-bot/internal/database"
+)
+
+// ApprovalStore records the workflow run approvals it would have stored, so the database is never opened
+type ApprovalStore struct {
+	recorder *Recorder
+	table    string
+}
+
+// NewApprovalStore creates an approval store for the given table
+func NewApprovalStore(recorder *Recorder, table string) *ApprovalStore {
+	return &ApprovalStore{
+		recorder: recorder,
+		table:    table,
+	}
+}
+
+// RecordApproval records the workflow run approval insert
+func (s *ApprovalStore) RecordApproval(approval database.Approval) error {
+	s.recorder.Record("database", "record workflow run approval", map[string]any{"table": s.table, "repository": approval.Repo, "PR": approval.PRNumber, "run": approval.RunID, "sha": approval.SHA, "reason": approval.Reason})
+	return nil
+}
+
+// Ping always succeeds since there is no connection to check
+func (s *ApprovalStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close does nothing since no connection was opened
+func (s *ApprovalStore) Close() error {
+	return nil
+}
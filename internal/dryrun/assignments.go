@@ -0,0 +1,73 @@
+package dryrun
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/chia-network/github-bot/internal/database"
+)
+
+// AssignmentStore keeps review assignments in memory and records the writes it would have made, so the database is never opened.
+// Later iterations in the same process see earlier assignments, which keeps reviewer balancing realistic.
+type AssignmentStore struct {
+	recorder *Recorder
+	table    string
+
+	mu          sync.Mutex
+	assignments []database.Assignment
+}
+
+// NewAssignmentStore creates an empty in-memory assignment store for the given table
+func NewAssignmentStore(recorder *Recorder, table string) *AssignmentStore {
+	return &AssignmentStore{
+		recorder: recorder,
+		table:    table,
+	}
+}
+
+// PRAssignments returns what earlier dry-run assignments stored for the PR
+func (s *AssignmentStore) PRAssignments(repo string, prNumber int64) ([]database.Assignment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var assignments []database.Assignment
+	for _, assignment := range s.assignments {
+		if assignment.Repo == repo && assignment.PRNumber == prNumber {
+			assignments = append(assignments, assignment)
+		}
+	}
+	return assignments, nil
+}
+
+// AssignmentsSince returns earlier dry-run assignments made at or after since
+func (s *AssignmentStore) AssignmentsSince(since time.Time) ([]database.Assignment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var assignments []database.Assignment
+	for _, assignment := range s.assignments {
+		if !assignment.AssignedAt.Before(since) {
+			assignments = append(assignments, assignment)
+		}
+	}
+	return assignments, nil
+}
+
+// RecordAssignment records the review assignment insert
+func (s *AssignmentStore) RecordAssignment(repo string, prNumber int64, reviewer string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.assignments = append(s.assignments, database.Assignment{Repo: repo, PRNumber: prNumber, Reviewer: reviewer, AssignedAt: time.Now().UTC()})
+
+	s.recorder.Record("database", "record review assignment", map[string]any{"table": s.table, "repository": repo, "PR": prNumber, "reviewer": reviewer})
+	return nil
+}
+
+// Ping always succeeds since there is no connection to check
+func (s *AssignmentStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close does nothing since no connection was opened
+func (s *AssignmentStore) Close() error {
+	return nil
+}
@@ -0,0 +1,28 @@
+package dryrun
+
+import (
+	"context"
+
+	"github.com/chia-network/github-bot/internal/notify"
+)
+
+// Notifier records messages instead of sending them
+type Notifier struct {
+	Recorder *Recorder
+	// Type is the configured notifier type, included in the record of each message
+	Type string
+}
+
+// Notify records the message that would have been sent
+func (n *Notifier) Notify(ctx context.Context, message notify.Message) error {
+	notifierType := n.Type
+	if notifierType == "" {
+		notifierType = "keybase"
+	}
+	n.Recorder.Record("notify", "send message", map[string]any{
+		"notifier":    notifierType,
+		"title":       message.Title,
+		"description": message.Description,
+	})
+	return nil
+}
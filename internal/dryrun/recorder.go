@@ -0,0 +1,69 @@
+package dryrun
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+)
+
+// Action is a side effect that was skipped because of dry-run mode
+type Action struct {
+	Time time.Time `json:"time"`
+	// Kind is the system the action would have touched: github, notify or database
+	Kind        string         `json:"kind"`
+	Description string         `json:"description"`
+	Details     map[string]any `json:"details,omitempty"`
+}
+
+// Recorder logs every skipped action and, when given a plan path, appends each one to it as a line of JSON
+type Recorder struct {
+	mu   sync.Mutex
+	plan *os.File
+}
+
+// New creates a recorder. planPath may be empty to only log actions.
+func New(planPath string) (*Recorder, error) {
+	r := &Recorder{}
+	if planPath != "" {
+		plan, err := os.OpenFile(planPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("error opening dry-run plan %s: %w", planPath, err)
+		}
+		r.plan = plan
+	}
+	return r, nil
+}
+
+// Record logs an action that would have been taken
+func (r *Recorder) Record(kind string, description string, details map[string]any) {
+	keys := make([]string, 0, len(details))
+	for key := range details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	args := []any{"kind", kind, "action", description}
+	for _, key := range keys {
+		args = append(args, key, details[key])
+	}
+	slogs.Logr.Info("Dry run: skipping action", args...)
+
+	if r.plan == nil {
+		return
+	}
+	line, err := json.Marshal(Action{Time: time.Now().UTC(), Kind: kind, Description: description, Details: details})
+	if err != nil {
+		slogs.Logr.Error("Error encoding dry-run action", "error", err)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.plan.Write(append(line, '\n')); err != nil {
+		slogs.Logr.Error("Error writing dry-run plan", "error", err)
+	}
+}
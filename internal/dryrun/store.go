@@ -0,0 +1,96 @@
+package dryrun
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/chia-network/github-bot/internal/database"
+)
+
+// Store keeps PR state in memory and records the writes it would have made, so the database is never opened.
+// Later iterations in the same process see earlier writes, which keeps re-notify behavior realistic.
+type Store struct {
+	recorder *Recorder
+	table    string
+
+	mu  sync.Mutex
+	prs map[storeKey]*database.PRInfo
+}
+
+type storeKey struct {
+	repo     string
+	prNumber int64
+}
+
+// NewStore creates an empty in-memory store for the given table
+func NewStore(recorder *Recorder, table string) *Store {
+	return &Store{
+		recorder: recorder,
+		table:    table,
+		prs:      map[storeKey]*database.PRInfo{},
+	}
+}
+
+// entry returns the PR's state, creating it if needed. The caller must hold mu.
+func (s *Store) entry(repo string, prNumber int64) *database.PRInfo {
+	key := storeKey{repo, prNumber}
+	prInfo, ok := s.prs[key]
+	if !ok {
+		prInfo = &database.PRInfo{Repo: repo, PRNumber: prNumber}
+		s.prs[key] = prInfo
+	}
+	return prInfo
+}
+
+// GetPRData returns what earlier dry-run writes stored, or nil
+func (s *Store) GetPRData(repo string, prNumber int64) (*database.PRInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prInfo, ok := s.prs[storeKey{repo, prNumber}]
+	if !ok {
+		return nil, nil
+	}
+	copied := *prInfo
+	return &copied, nil
+}
+
+// StorePRData records the last_message_sent update
+func (s *Store) StorePRData(repo string, prNumber int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entry(repo, prNumber).LastMessageSent = time.Now().UTC()
+
+	s.recorder.Record("database", "store last message sent", map[string]any{"table": s.table, "repository": repo, "PR": prNumber})
+	return nil
+}
+
+// UpdateSuppressMessages records the suppress_messages update
+func (s *Store) UpdateSuppressMessages(repo string, prNumber int64, suppress bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entry(repo, prNumber).SuppressMessages = suppress
+
+	s.recorder.Record("database", "update suppress_messages", map[string]any{"table": s.table, "repository": repo, "PR": prNumber, "suppress": suppress})
+	return nil
+}
+
+// SnoozeUntil records the snoozed_until update
+func (s *Store) SnoozeUntil(repo string, prNumber int64, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entry(repo, prNumber).SnoozedUntil = until
+
+	s.recorder.Record("database", "update snoozed_until", map[string]any{"table": s.table, "repository": repo, "PR": prNumber, "until": until})
+	return nil
+}
+
+// Ping always succeeds since there is no connection to check
+func (s *Store) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close does nothing since no connection was opened
+func (s *Store) Close() error {
+	return nil
+}
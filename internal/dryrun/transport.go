@@ -0,0 +1,75 @@
+package dryrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+)
+
+// Transport passes reads through to GitHub and records every other request instead of sending it
+type Transport struct {
+	Recorder *Recorder
+	Base     http.RoundTripper
+}
+
+var githubActions = []struct {
+	method  string
+	path    *regexp.Regexp
+	summary string
+}{
+	{http.MethodPost, regexp.MustCompile(`/repos/[^/]+/[^/]+/issues/\d+/labels$`), "add labels"},
+	{http.MethodDelete, regexp.MustCompile(`/repos/[^/]+/[^/]+/issues/\d+/labels/[^/]+$`), "remove label"},
+	{http.MethodPost, regexp.MustCompile(`/repos/[^/]+/[^/]+/issues/\d+/comments$`), "post comment"},
+	{http.MethodPatch, regexp.MustCompile(`/repos/[^/]+/[^/]+/issues/comments/\d+$`), "edit comment"},
+	{http.MethodDelete, regexp.MustCompile(`/repos/[^/]+/[^/]+/issues/comments/\d+$`), "delete comment"},
+	{http.MethodPost, regexp.MustCompile(`/repos/[^/]+/[^/]+/actions/runs/\d+/approve$`), "approve workflow run"},
+	{http.MethodPost, regexp.MustCompile(`/repos/[^/]+/[^/]+/pulls/\d+/requested_reviewers$`), "request reviewers"},
+	{http.MethodPost, regexp.MustCompile(`/repos/[^/]+/[^/]+/statuses/[0-9a-f]+$`), "set commit status"},
+	{http.MethodPost, regexp.MustCompile(`/repos/[^/]+/[^/]+/check-runs$`), "create check run"},
+	{http.MethodPatch, regexp.MustCompile(`/repos/[^/]+/[^/]+/check-runs/\d+$`), "update check run"},
+}
+
+// RoundTrip records mutating requests and answers them with an empty 204, which go-github treats as success
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.Base.RoundTrip(req)
+	}
+
+	details := map[string]any{"method": req.Method, "path": req.URL.Path}
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading request body: %w", err)
+		}
+		var decoded any
+		if json.Unmarshal(body, &decoded) == nil {
+			details["body"] = decoded
+		} else if len(body) > 0 {
+			details["body"] = string(body)
+		}
+	}
+
+	summary := fmt.Sprintf("%s %s", req.Method, req.URL.Path)
+	for _, action := range githubActions {
+		if action.method == req.Method && action.path.MatchString(req.URL.Path) {
+			summary = action.summary
+			break
+		}
+	}
+	t.Recorder.Record("github", summary, details)
+
+	return &http.Response{
+		Status:     "204 No Content",
+		StatusCode: http.StatusNoContent,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package dryrun
+
+import (
+	"context"
+	"sync"
+)
+
+// WelcomeStore keeps welcomed authors in memory and records the writes it would have made, so the database is never opened.
+// Later iterations in the same process see earlier welcomes, so authors aren't welcomed on every iteration.
+type WelcomeStore struct {
+	recorder *Recorder
+	table    string
+
+	mu       sync.Mutex
+	welcomed map[welcomeKey]bool
+}
+
+type welcomeKey struct {
+	repo   string
+	author string
+}
+
+// NewWelcomeStore creates an empty in-memory welcome store for the given table
+func NewWelcomeStore(recorder *Recorder, table string) *WelcomeStore {
+	return &WelcomeStore{
+		recorder: recorder,
+		table:    table,
+		welcomed: map[welcomeKey]bool{},
+	}
+}
+
+// Welcomed reports whether an earlier dry-run welcome was recorded for the author
+func (s *WelcomeStore) Welcomed(repo string, author string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.welcomed[welcomeKey{repo, author}], nil
+}
+
+// RecordWelcome records the welcomed author insert
+func (s *WelcomeStore) RecordWelcome(repo string, author string, prNumber int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.welcomed[welcomeKey{repo, author}] = true
+
+	s.recorder.Record("database", "record welcomed author", map[string]any{"table": s.table, "repository": repo, "author": author, "PR": prNumber})
+	return nil
+}
+
+// Ping always succeeds since there is no connection to check
+func (s *WelcomeStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close does nothing since no connection was opened
+func (s *WelcomeStore) Close() error {
+	return nil
+}
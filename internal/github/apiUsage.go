@@ -0,0 +1,91 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+
+	"github.com/chia-network/github-bot/internal/metrics"
+)
+
+type jobContextKey struct{}
+
+// WithJob tags ctx so GitHub API calls made with it are counted against the named job
+func WithJob(ctx context.Context, job string) context.Context {
+	return context.WithValue(ctx, jobContextKey{}, job)
+}
+
+func jobFromContext(ctx context.Context) string {
+	if job, ok := ctx.Value(jobContextKey{}).(string); ok {
+		return job
+	}
+	return "untracked"
+}
+
+// usageTracker counts API calls per job and repository and remembers the last budget GitHub reported
+type usageTracker struct {
+	mu        sync.Mutex
+	calls     map[string]map[string]int
+	limit     int
+	remaining int
+	reset     time.Time
+}
+
+var apiUsage = &usageTracker{calls: map[string]map[string]int{}, remaining: -1}
+
+func (u *usageTracker) record(ctx context.Context, req *http.Request) {
+	job := jobFromContext(ctx)
+	repo := repoFromPath(req.URL.Path)
+	if repo == "" {
+		repo = "other"
+	}
+
+	metrics.APICalls.WithLabelValues(job, repo).Inc()
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.calls[job] == nil {
+		u.calls[job] = map[string]int{}
+	}
+	u.calls[job][repo]++
+}
+
+func (u *usageTracker) setBudget(limit, remaining int, reset time.Time) {
+	metrics.SetRateLimit(remaining, reset)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.limit = limit
+	u.remaining = remaining
+	u.reset = reset
+}
+
+// LogAPIUsage logs the API calls the job made since the last summary, per repository, along with the remaining budget
+func LogAPIUsage(job string) {
+	apiUsage.mu.Lock()
+	calls := apiUsage.calls[job]
+	delete(apiUsage.calls, job)
+	limit, remaining, reset := apiUsage.limit, apiUsage.remaining, apiUsage.reset
+	apiUsage.mu.Unlock()
+
+	repos := make([]string, 0, len(calls))
+	total := 0
+	for repo, count := range calls {
+		repos = append(repos, repo)
+		total += count
+	}
+	sort.Strings(repos)
+	for _, repo := range repos {
+		slogs.Logr.Info("GitHub API calls", "job", job, "repository", repo, "calls", calls[repo])
+	}
+
+	args := []any{"job", job, "calls", total}
+	if remaining >= 0 {
+		args = append(args, "remaining", remaining, "limit", limit, "reset", reset.Format(time.RFC3339))
+	}
+	slogs.Logr.Info("GitHub API usage for cycle", args...)
+}
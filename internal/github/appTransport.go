@@ -0,0 +1,190 @@
+package github
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/go-github/v60/github"
+
+	"github.com/chia-network/github-bot/internal/config"
+)
+
+// appTransport authenticates each request with an installation token for the org or user that owns the target repository,
+// minting tokens as needed and refreshing them shortly before they expire
+type appTransport struct {
+	appID      int64
+	privateKey *rsa.PrivateKey
+	base       http.RoundTripper
+
+	// appClient is authenticated with the app's JWT and is only used to find installations and mint tokens
+	appClient *github.Client
+
+	// defaultOwner is used for requests whose URL does not name an owner
+	defaultOwner string
+
+	mu            sync.Mutex
+	installations map[string]int64
+	tokens        map[int64]*github.InstallationToken
+}
+
+func newAppTransport(cfg config.GithubAppConfig, base http.RoundTripper) (*appTransport, error) {
+	keyPEM := []byte(cfg.PrivateKey)
+	if len(keyPEM) == 0 {
+		var err error
+		keyPEM, err = os.ReadFile(cfg.PrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading GitHub App private key: %w", err)
+		}
+	}
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing GitHub App private key: %w", err)
+	}
+
+	t := &appTransport{
+		appID:         cfg.AppID,
+		privateKey:    privateKey,
+		base:          base,
+		installations: map[string]int64{},
+		tokens:        map[int64]*github.InstallationToken{},
+	}
+	t.appClient = github.NewClient(&http.Client{Transport: &jwtTransport{app: t, base: base}})
+	return t, nil
+}
+
+// jwt returns a short-lived token identifying the app itself
+func (t *appTransport) jwt() (string, error) {
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		Issuer: strconv.FormatInt(t.appID, 10),
+		// Backdated to allow for clock drift, as recommended by GitHub
+		IssuedAt:  jwt.NewNumericDate(now.Add(-60 * time.Second)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(9 * time.Minute)),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(t.privateKey)
+}
+
+// installationFor returns the installation ID for an owner, relisting installations when the owner is not known yet
+func (t *appTransport) installationFor(ctx context.Context, owner string) (int64, error) {
+	key := strings.ToLower(owner)
+	t.mu.Lock()
+	id, ok := t.installations[key]
+	t.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	opts := &github.ListOptions{PerPage: 100}
+	installations := map[string]int64{}
+	for {
+		page, resp, err := t.appClient.Apps.ListInstallations(ctx, opts)
+		if err != nil {
+			return 0, fmt.Errorf("error listing GitHub App installations: %w", err)
+		}
+		for _, installation := range page {
+			installations[strings.ToLower(installation.GetAccount().GetLogin())] = installation.GetID()
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	t.mu.Lock()
+	t.installations = installations
+	t.mu.Unlock()
+
+	id, ok = installations[key]
+	if !ok {
+		return 0, fmt.Errorf("no installation of the GitHub App for %s", owner)
+	}
+	return id, nil
+}
+
+// token returns a cached installation token, minting a new one when it is missing or about to expire
+func (t *appTransport) token(ctx context.Context, installationID int64) (string, error) {
+	t.mu.Lock()
+	cached := t.tokens[installationID]
+	t.mu.Unlock()
+	if cached != nil && time.Until(cached.GetExpiresAt().Time) > 5*time.Minute {
+		return cached.GetToken(), nil
+	}
+
+	token, _, err := t.appClient.Apps.CreateInstallationToken(ctx, installationID, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating token for installation %d: %w", installationID, err)
+	}
+
+	t.mu.Lock()
+	t.tokens[installationID] = token
+	t.mu.Unlock()
+	return token.GetToken(), nil
+}
+
+// RoundTrip adds the installation token for the request's owner
+func (t *appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	owner := ownerFromRequest(req)
+	if owner == "" {
+		owner = t.defaultOwner
+	}
+
+	installationID, err := t.installationFor(req.Context(), owner)
+	if err != nil {
+		return nil, err
+	}
+	token, err := t.token(req.Context(), installationID)
+	if err != nil {
+		return nil, err
+	}
+
+	authed := req.Clone(req.Context())
+	authed.Header.Set("Authorization", fmt.Sprintf("token %s", token))
+	return t.base.RoundTrip(authed)
+}
+
+// ownerFromRequest extracts the org or user a REST API request is for, e.g. /repos/{owner}/..., /orgs/{org}/...,
+// or a repo: qualifier in a search query
+func ownerFromRequest(req *http.Request) string {
+	parts := apiPath(req.URL.Path)
+	if len(parts) >= 2 {
+		switch parts[0] {
+		case "repos", "orgs", "users":
+			return parts[1]
+		}
+	}
+	if len(parts) >= 1 && parts[0] == "search" {
+		for _, term := range strings.Fields(req.URL.Query().Get("q")) {
+			for _, qualifier := range []string{"repo:", "org:", "user:"} {
+				if value, ok := strings.CutPrefix(term, qualifier); ok {
+					owner, _, _ := strings.Cut(value, "/")
+					return owner
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// jwtTransport authenticates requests as the app itself
+type jwtTransport struct {
+	app  *appTransport
+	base http.RoundTripper
+}
+
+func (t *jwtTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.app.jwt()
+	if err != nil {
+		return nil, fmt.Errorf("error signing GitHub App JWT: %w", err)
+	}
+	authed := req.Clone(req.Context())
+	authed.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	return t.base.RoundTrip(authed)
+}
@@ -0,0 +1,263 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/google/go-github/v60/github"
+
+	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/database"
+	"github.com/chia-network/github-bot/internal/metrics"
+)
+
+// ReviewAssignment is a community PR the bot requested reviews on
+type ReviewAssignment struct {
+	Owner     string
+	Repo      string
+	PRNumber  int
+	URL       string
+	Reviewers []string
+}
+
+// AssignReviewers requests reviews on community PRs that have no reviewer yet from the internal team members who own
+// the changed files in CODEOWNERS, and records each request in the store so later picks can be balanced
+func AssignReviewers(ctx context.Context, githubClient *github.Client, cfg *config.Config, snapshot *Snapshot, store database.AssignmentStore) ([]ReviewAssignment, error) {
+	var assignments []ReviewAssignment
+	teamMembers, err := snapshot.TeamMembers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resolver := &ownerResolver{client: githubClient, cfg: cfg, teamMembers: teamMembers, teams: map[string][]string{}}
+
+	for _, fullRepo := range cfg.CheckRepos {
+		slogs.Logr.Info("Checking repository", "repository", fullRepo.Name)
+		parts := strings.Split(fullRepo.Name, "/")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid repository name - must contain owner and repository: %s", fullRepo.Name)
+		}
+		owner, repo := parts[0], parts[1]
+
+		communityPRs, err := snapshot.CommunityPRs(ctx, owner, repo, fullRepo.MinimumNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		// CODEOWNERS is read from each PR's base branch, which is almost always the same one
+		codeOwners := map[string]*CodeOwners{}
+		for _, pr := range communityPRs {
+			base := pr.GetBase().GetRef()
+			if _, ok := codeOwners[base]; !ok {
+				codeOwners[base], err = GetCodeOwners(ctx, githubClient, owner, repo, base)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if codeOwners[base] == nil {
+				slogs.Logr.Info("Repository has no CODEOWNERS, skipping reviewer assignment", "repository", fullRepo.Name, "branch", base)
+				continue
+			}
+
+			reviewers, err := assignPRReviewers(ctx, githubClient, cfg, store, resolver, codeOwners[base], pr)
+			if err != nil {
+				slogs.Logr.Error("Error assigning reviewers", "PR", pr.GetNumber(), "repository", fullRepo.Name, "error", err)
+				continue
+			}
+			if len(reviewers) > 0 {
+				assignments = append(assignments, ReviewAssignment{
+					Owner:     owner,
+					Repo:      repo,
+					PRNumber:  pr.GetNumber(),
+					URL:       pr.GetHTMLURL(),
+					Reviewers: reviewers,
+				})
+			}
+		}
+	}
+
+	return assignments, nil
+}
+
+// assignPRReviewers requests reviews on a single PR, returning who was requested
+func assignPRReviewers(ctx context.Context, githubClient *github.Client, cfg *config.Config, store database.AssignmentStore, resolver *ownerResolver, codeOwners *CodeOwners, pr *github.PullRequest) ([]string, error) {
+	owner, repo, number := pr.GetBase().GetRepo().GetOwner().GetLogin(), pr.GetBase().GetRepo().GetName(), pr.GetNumber()
+	fullRepo := owner + "/" + repo
+
+	if len(pr.RequestedReviewers) > 0 || len(pr.RequestedTeams) > 0 {
+		slogs.Logr.Info("PR already has reviewers requested", "PR", number, "repository", fullRepo)
+		return nil, nil
+	}
+	previous, err := store.PRAssignments(fullRepo, int64(number))
+	if err != nil {
+		return nil, err
+	}
+	if len(previous) > 0 {
+		// The requested reviewers have since reviewed or been removed, which is not ours to undo
+		slogs.Logr.Info("Reviewers were already assigned to PR", "PR", number, "repository", fullRepo)
+		return nil, nil
+	}
+	reviewed, err := hasTeamReview(ctx, githubClient, owner, repo, number, resolver.teamMembers)
+	if err != nil {
+		return nil, err
+	}
+	if reviewed {
+		slogs.Logr.Info("PR was already reviewed by a team member", "PR", number, "repository", fullRepo)
+		return nil, nil
+	}
+
+	files, err := ChangedFiles(ctx, githubClient, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	candidates := map[string]bool{}
+	for _, file := range files {
+		for _, codeOwner := range codeOwners.Owners(file) {
+			members, err := resolver.resolve(ctx, codeOwner)
+			if err != nil {
+				return nil, err
+			}
+			for _, member := range members {
+				candidates[member] = true
+			}
+		}
+	}
+	delete(candidates, pr.GetUser().GetLogin())
+	if len(candidates) == 0 {
+		slogs.Logr.Info("No internal team member owns the files changed by the PR", "PR", number, "repository", fullRepo)
+		return nil, nil
+	}
+
+	history, err := store.AssignmentsSince(time.Now().Add(-cfg.Reviewers.LoadWindow))
+	if err != nil {
+		return nil, err
+	}
+	reviewers := pickReviewers(candidates, history, cfg.Reviewers.Strategy, cfg.Reviewers.Count)
+
+	slogs.Logr.Info("Requesting reviews from code owners", "PR", number, "repository", fullRepo, "reviewers", reviewers, "strategy", cfg.Reviewers.Strategy)
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	_, _, err = githubClient.PullRequests.RequestReviewers(reqCtx, owner, repo, number, github.ReviewersRequest{Reviewers: reviewers})
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("error requesting reviewers: %w", err)
+	}
+	for _, reviewer := range reviewers {
+		metrics.ReviewsRequested.WithLabelValues(fullRepo).Inc()
+		err = store.RecordAssignment(fullRepo, int64(number), reviewer)
+		if err != nil {
+			return reviewers, err
+		}
+	}
+	return reviewers, nil
+}
+
+// hasTeamReview reports whether a team member has already submitted a review on the PR
+func hasTeamReview(ctx context.Context, githubClient *github.Client, owner, repo string, number int, teamMembers map[string]bool) (bool, error) {
+	listOptions := &github.ListOptions{PerPage: 100}
+	for {
+		reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		reviews, resp, err := githubClient.PullRequests.ListReviews(reqCtx, owner, repo, number, listOptions)
+		cancel()
+		if err != nil {
+			return false, fmt.Errorf("error listing reviews: %w", err)
+		}
+		for _, review := range reviews {
+			if teamMembers[review.GetUser().GetLogin()] {
+				return true, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return false, nil
+		}
+		listOptions.Page = resp.NextPage
+	}
+}
+
+// pickReviewers orders the candidates by the strategy and returns the first count of them.
+// round_robin prefers whoever was assigned least recently; least_loaded prefers the fewest assignments in the history,
+// falling back to least recently assigned. Candidates never assigned come first, in alphabetical order.
+func pickReviewers(candidates map[string]bool, history []database.Assignment, strategy string, count int) []string {
+	loads := map[string]int{}
+	lastAssigned := map[string]time.Time{}
+	for _, assignment := range history {
+		loads[assignment.Reviewer]++
+		if assignment.AssignedAt.After(lastAssigned[assignment.Reviewer]) {
+			lastAssigned[assignment.Reviewer] = assignment.AssignedAt
+		}
+	}
+
+	ordered := make([]string, 0, len(candidates))
+	for candidate := range candidates {
+		ordered = append(ordered, candidate)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if strategy == config.ReviewStrategyLeastLoaded && loads[a] != loads[b] {
+			return loads[a] < loads[b]
+		}
+		if !lastAssigned[a].Equal(lastAssigned[b]) {
+			return lastAssigned[a].Before(lastAssigned[b])
+		}
+		return a < b
+	})
+
+	if len(ordered) > count {
+		ordered = ordered[:count]
+	}
+	return ordered
+}
+
+// ownerResolver turns CODEOWNERS entries into the internal team members they name, caching team lookups for a cycle
+type ownerResolver struct {
+	client      *github.Client
+	cfg         *config.Config
+	teamMembers map[string]bool
+	teams       map[string][]string
+}
+
+// resolve returns the internal team members named by a CODEOWNERS owner: a @user, an @org/team or an email address.
+// Email addresses can't be mapped to a login without extra permissions, so they are skipped.
+func (r *ownerResolver) resolve(ctx context.Context, codeOwner string) ([]string, error) {
+	name, ok := strings.CutPrefix(codeOwner, "@")
+	if !ok {
+		return nil, nil
+	}
+
+	org, team, isTeam := strings.Cut(name, "/")
+	if !isTeam {
+		for member := range r.teamMembers {
+			if strings.EqualFold(member, name) {
+				return []string{member}, nil
+			}
+		}
+		return nil, nil
+	}
+
+	key := strings.ToLower(name)
+	if members, ok := r.teams[key]; ok {
+		return members, nil
+	}
+	var members []string
+	if strings.EqualFold(name, r.cfg.InternalTeam) {
+		for member := range r.teamMembers {
+			members = append(members, member)
+		}
+	} else {
+		// Only the internal team's members are eligible, so ignored users stay ignored
+		teamMembers, err := GetTeamMemberList(ctx, r.client, org+"/"+team, nil)
+		if err != nil {
+			// Teams in CODEOWNERS may be secret or from another org; the other owners can still be used
+			slogs.Logr.Warn("Could not list members of code owner team", "team", name, "error", err)
+		}
+		for member := range teamMembers {
+			if r.teamMembers[member] {
+				members = append(members, member)
+			}
+		}
+	}
+	r.teams[key] = members
+	return members, nil
+}
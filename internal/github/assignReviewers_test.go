@@ -0,0 +1,101 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/database"
+)
+
+func TestPickReviewers(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	assigned := func(reviewer string, hours int) database.Assignment {
+		return database.Assignment{Repo: "o/r", Reviewer: reviewer, AssignedAt: start.Add(time.Duration(hours) * time.Hour)}
+	}
+	candidates := map[string]bool{"alice": true, "bob": true, "carol": true, "dave": true}
+
+	tests := []struct {
+		name     string
+		strategy string
+		history  []database.Assignment
+		count    int
+		want     []string
+	}{
+		{
+			name:     "no history is alphabetical",
+			strategy: config.ReviewStrategyRoundRobin,
+			count:    2,
+			want:     []string{"alice", "bob"},
+		},
+		{
+			name:     "round robin puts never assigned first, then least recently assigned",
+			strategy: config.ReviewStrategyRoundRobin,
+			history:  []database.Assignment{assigned("alice", 3), assigned("bob", 1), assigned("carol", 2)},
+			count:    3,
+			want:     []string{"dave", "bob", "carol"},
+		},
+		{
+			name:     "round robin uses each reviewer's latest assignment",
+			strategy: config.ReviewStrategyRoundRobin,
+			history:  []database.Assignment{assigned("alice", 1), assigned("bob", 2), assigned("alice", 5), assigned("carol", 3), assigned("dave", 4)},
+			count:    2,
+			want:     []string{"bob", "carol"},
+		},
+		{
+			name:     "round robin ties on the same time are alphabetical",
+			strategy: config.ReviewStrategyRoundRobin,
+			history:  []database.Assignment{assigned("dave", 1), assigned("carol", 1), assigned("bob", 1), assigned("alice", 1)},
+			count:    4,
+			want:     []string{"alice", "bob", "carol", "dave"},
+		},
+		{
+			name:     "round robin ignores how many reviews someone has",
+			strategy: config.ReviewStrategyRoundRobin,
+			history:  []database.Assignment{assigned("alice", 1), assigned("alice", 2), assigned("alice", 3), assigned("bob", 4), assigned("carol", 5), assigned("dave", 6)},
+			count:    1,
+			want:     []string{"alice"},
+		},
+		{
+			name:     "least loaded prefers the fewest assignments",
+			strategy: config.ReviewStrategyLeastLoaded,
+			history:  []database.Assignment{assigned("alice", 1), assigned("alice", 2), assigned("alice", 3), assigned("bob", 4), assigned("carol", 5), assigned("dave", 6)},
+			count:    4,
+			want:     []string{"bob", "carol", "dave", "alice"},
+		},
+		{
+			name:     "least loaded ties fall back to least recently assigned",
+			strategy: config.ReviewStrategyLeastLoaded,
+			history:  []database.Assignment{assigned("alice", 4), assigned("bob", 3), assigned("carol", 2), assigned("dave", 1), assigned("dave", 5)},
+			count:    3,
+			want:     []string{"carol", "bob", "alice"},
+		},
+		{
+			name:     "least loaded ties on load and time are alphabetical",
+			strategy: config.ReviewStrategyLeastLoaded,
+			history:  []database.Assignment{assigned("carol", 1), assigned("bob", 1), assigned("dave", 2), assigned("dave", 3)},
+			count:    3,
+			want:     []string{"alice", "bob", "carol"},
+		},
+		{
+			name:     "count larger than the candidates returns them all",
+			strategy: config.ReviewStrategyLeastLoaded,
+			count:    10,
+			want:     []string{"alice", "bob", "carol", "dave"},
+		},
+		{
+			name:     "history for people who are no longer candidates is ignored",
+			strategy: config.ReviewStrategyLeastLoaded,
+			history:  []database.Assignment{assigned("erin", 1), assigned("alice", 2)},
+			count:    4,
+			want:     []string{"bob", "carol", "dave", "alice"},
+		},
+	}
+	for _, test := range tests {
+		got := pickReviewers(candidates, test.history, test.strategy, test.count)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: pickReviewers() = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
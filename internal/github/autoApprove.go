@@ -0,0 +1,160 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/google/go-github/v60/github"
+
+	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/database"
+	"github.com/chia-network/github-bot/internal/metrics"
+)
+
+// AutoApproveRuns approves a PR's fork workflow runs that are awaiting approval when auto_approve is enabled,
+// the author is trusted and the PR changes none of the repo's sensitive paths. Each approval is recorded in store.
+// It returns why the author was trusted when any run was approved, and an empty string otherwise. The reason is returned
+// along with the error when a later run fails, so the runs that were approved are still announced.
+func AutoApproveRuns(ctx context.Context, client *github.Client, cfg *config.Config, store database.ApprovalStore, owner, repo string, pr *github.PullRequest) (string, error) {
+	if !cfg.AutoApprove.Enabled {
+		return "", nil
+	}
+	fullRepo := fmt.Sprintf("%s/%s", owner, repo)
+	author := pr.GetUser().GetLogin()
+
+	runs, err := actionRequiredRuns(ctx, client, owner, repo, pr.GetHead().GetSHA())
+	if err != nil {
+		return "", err
+	}
+	if len(runs) == 0 {
+		return "", nil
+	}
+
+	reason, err := trustedAuthor(ctx, client, cfg, owner, repo, author)
+	if err != nil {
+		return "", err
+	}
+	if reason == "" {
+		slogs.Logr.Info("Not auto-approving workflow runs, author does not meet the trust criteria", "PR", pr.GetNumber(), "repository", fullRepo, "user", author)
+		return "", nil
+	}
+
+	// Each run is checked against the files at its own commit rather than the PR's current files, since the author can push
+	// again between listing the runs and approving them, e.g. reverting a workflow change after the run for it was queued
+	safeSHAs := map[string]bool{}
+	approved := false
+	// approvedReason is only returned once a run was actually approved
+	approvedReason := func() string {
+		if approved {
+			return reason
+		}
+		return ""
+	}
+	for _, run := range runs {
+		sha := run.GetHeadSHA()
+		safe, checked := safeSHAs[sha]
+		if !checked {
+			safe, err = safeToApprove(ctx, client, cfg, owner, repo, pr, sha)
+			if err != nil {
+				return approvedReason(), err
+			}
+			safeSHAs[sha] = safe
+		}
+		if !safe {
+			continue
+		}
+		err = approveWorkflowRun(ctx, client, owner, repo, run.GetID())
+		if err != nil {
+			return approvedReason(), fmt.Errorf("error approving workflow run %d: %w", run.GetID(), err)
+		}
+		approved = true
+		metrics.AutoApprovals.WithLabelValues(fullRepo).Inc()
+		slogs.Logr.Info("Auto-approved workflow run", "PR", pr.GetNumber(), "repository", fullRepo, "user", author, "run", run.GetID(), "workflow", run.GetName(), "sha", sha, "reason", reason)
+		if store != nil {
+			err = store.RecordApproval(database.Approval{Repo: fullRepo, PRNumber: int64(pr.GetNumber()), RunID: run.GetID(), SHA: sha, Reason: reason})
+			if err != nil {
+				slogs.Logr.Error("Error recording workflow run approval", "PR", pr.GetNumber(), "repository", fullRepo, "run", run.GetID(), "error", err)
+			}
+		}
+	}
+	return approvedReason(), nil
+}
+
+// safeToApprove reports whether the commit changes none of the repo's sensitive paths compared to the PR's base branch.
+// Comparisons too large for GitHub to list every file are never safe, since the files left out could be sensitive.
+func safeToApprove(ctx context.Context, client *github.Client, cfg *config.Config, owner, repo string, pr *github.PullRequest, sha string) (bool, error) {
+	fullRepo := fmt.Sprintf("%s/%s", owner, repo)
+	files, complete, err := ChangedFilesAt(ctx, client, owner, repo, pr.GetBase().GetRef(), sha)
+	if err != nil {
+		return false, err
+	}
+	if !complete {
+		slogs.Logr.Warn("Not auto-approving workflow runs, commit changes too many files to check", "PR", pr.GetNumber(), "repository", fullRepo, "sha", sha)
+		return false, nil
+	}
+	if sensitive := MatchingPaths(files, cfg.PolicyFor(fullRepo).SensitivePaths); len(sensitive) > 0 {
+		slogs.Logr.Warn("Not auto-approving workflow runs, commit changes sensitive paths", "PR", pr.GetNumber(), "repository", fullRepo, "sha", sha, "files", sensitive)
+		return false, nil
+	}
+	return true, nil
+}
+
+// trustedAuthor returns which of the auto_approve criteria the author meets, or an empty string if none
+func trustedAuthor(ctx context.Context, client *github.Client, cfg *config.Config, owner, repo, author string) (string, error) {
+	for _, user := range cfg.AutoApprove.AllowedUsers {
+		if strings.EqualFold(user, author) {
+			return "allowlisted user", nil
+		}
+	}
+
+	if cfg.AutoApprove.OrgMembers {
+		reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		member, _, err := client.Organizations.IsMember(reqCtx, owner, author)
+		cancel()
+		if err != nil {
+			return "", fmt.Errorf("error checking %s membership of %s: %w", author, owner, err)
+		}
+		if member {
+			return fmt.Sprintf("member of %s", owner), nil
+		}
+	}
+
+	if cfg.AutoApprove.MinMergedPRs > 0 {
+		merged, err := mergedPRCount(ctx, client, owner, repo, author)
+		if err != nil {
+			return "", err
+		}
+		if merged >= cfg.AutoApprove.MinMergedPRs {
+			return fmt.Sprintf("%d merged PRs in %s/%s", merged, owner, repo), nil
+		}
+	}
+
+	return "", nil
+}
+
+// mergedPRCount returns how many of the author's PRs were merged in the repo
+func mergedPRCount(ctx context.Context, client *github.Client, owner, repo, author string) (int, error) {
+	query := fmt.Sprintf("repo:%s/%s is:pr is:merged author:%s", owner, repo, author)
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	result, _, err := client.Search.Issues(reqCtx, query, &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 1}})
+	if err != nil {
+		return 0, fmt.Errorf("error counting merged PRs by %s: %w", author, err)
+	}
+	return result.GetTotal(), nil
+}
+
+// approveWorkflowRun approves a fork workflow run, which go-github does not have a method for
+func approveWorkflowRun(ctx context.Context, client *github.Client, owner, repo string, runID int64) error {
+	req, err := client.NewRequest("POST", fmt.Sprintf("repos/%s/%s/actions/runs/%d/approve", owner, repo, runID), nil)
+	if err != nil {
+		return err
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	_, err = client.Do(reqCtx, req, nil)
+	return err
+}
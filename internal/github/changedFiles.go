@@ -0,0 +1,90 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/google/go-github/v60/github"
+)
+
+// ListFiles returns the files a PR changes
+func ListFiles(ctx context.Context, client *github.Client, owner, repo string, prNumber int) ([]*github.CommitFile, error) {
+	var files []*github.CommitFile
+	listOptions := &github.ListOptions{PerPage: 100}
+	for {
+		reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		page, resp, err := client.PullRequests.ListFiles(reqCtx, owner, repo, prNumber, listOptions)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("error listing files for PR #%d of repo %s: %w", prNumber, repo, err)
+		}
+		files = append(files, page...)
+		if resp.NextPage == 0 {
+			return files, nil
+		}
+		listOptions.Page = resp.NextPage
+	}
+}
+
+// ChangedFiles lists the paths a PR adds, modifies, removes or renames, including the old name of renamed files
+func ChangedFiles(ctx context.Context, client *github.Client, owner, repo string, prNumber int) ([]string, error) {
+	files, err := ListFiles(ctx, client, owner, repo, prNumber)
+	if err != nil {
+		return nil, err
+	}
+	return FileNames(files), nil
+}
+
+// maxCompareFiles is the most files GitHub lists when comparing two commits
+const maxCompareFiles = 300
+
+// ChangedFilesAt lists the paths changed by sha since it diverged from base, including the old name of renamed files.
+// complete is false when the comparison has more files than GitHub lists.
+func ChangedFilesAt(ctx context.Context, client *github.Client, owner, repo, base, sha string) (names []string, complete bool, err error) {
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	comparison, _, err := client.Repositories.CompareCommits(reqCtx, owner, repo, base, sha, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("error comparing %s...%s in repo %s: %w", base, sha, repo, err)
+	}
+	return FileNames(comparison.Files), len(comparison.Files) < maxCompareFiles, nil
+}
+
+// FileNames returns the paths of the files, including the old name of renamed files
+func FileNames(files []*github.CommitFile) []string {
+	var names []string
+	for _, file := range files {
+		names = append(names, file.GetFilename())
+		if previous := file.GetPreviousFilename(); previous != "" {
+			names = append(names, previous)
+		}
+	}
+	return names
+}
+
+// ChangedLines adds up additions and deletions, leaving out files matching the exclude globs
+func ChangedLines(files []*github.CommitFile, exclude []string) int {
+	total := 0
+	for _, file := range files {
+		if len(MatchingPaths([]string{file.GetFilename()}, exclude)) == 0 {
+			total += file.GetAdditions() + file.GetDeletions()
+		}
+	}
+	return total
+}
+
+// MatchingPaths returns the paths matched by any of the globs
+func MatchingPaths(paths []string, globs []string) []string {
+	var matched []string
+	for _, path := range paths {
+		for _, glob := range globs {
+			if ok, _ := doublestar.Match(glob, path); ok {
+				matched = append(matched, path)
+				break
+			}
+		}
+	}
+	return matched
+}
@@ -10,18 +10,30 @@ import (
 	"github.com/google/go-github/v60/github" // Ensure your go-github library version matches
 
 	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/database"
 )
 
 // PendingPR holds information about pending PRs
 type PendingPR struct {
+	Owner    string
 	Repo     string
 	PRNumber int
 	URL      string
+	Title    string
+	Author   string
+	// CreatedAt and LastTeamActivity are shown in digest messages. LastTeamActivity is zero if the team never commented or reviewed.
+	CreatedAt        time.Time
+	LastTeamActivity time.Time
+	// AutoApprovedReason is set when the PR's workflow runs were approved automatically, to why its author is trusted
+	AutoApprovedReason string
+	// SensitiveFiles are the changed files matching sensitive_paths, which the maintainer should review before approving
+	SensitiveFiles []string
 }
 
 // CheckForPendingCI returns a list of PR URLs that are ready for CI to run but haven't started yet.
-func CheckForPendingCI(ctx context.Context, githubClient *github.Client, cfg *config.Config) ([]PendingPR, error) {
-	teamMembers, err := GetTeamMemberList(githubClient, cfg.InternalTeam, cfg.InternalTeamIgnoredUsers)
+// PRs whose runs were auto-approved are included with AutoApprovedReason set so they can be announced.
+func CheckForPendingCI(ctx context.Context, githubClient *github.Client, cfg *config.Config, snapshot *Snapshot, approvalStore database.ApprovalStore) ([]PendingPR, error) {
+	teamMembers, err := snapshot.TeamMembers(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -36,61 +48,112 @@ func CheckForPendingCI(ctx context.Context, githubClient *github.Client, cfg *co
 		}
 		owner, repo := parts[0], parts[1]
 
-		// Fetch community PRs using the FindCommunityPRs function
-		communityPRs, err := FindCommunityPRs(cfg, teamMembers, githubClient, owner, repo, fullRepo.MinimumNumber)
+		// Fetch community PRs from the snapshot shared with other jobs
+		communityPRs, err := snapshot.CommunityPRs(ctx, owner, repo, fullRepo.MinimumNumber)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, pr := range communityPRs {
-			slogs.Logr.Info("Checking PR", "PR", pr.GetHTMLURL())
-			prctx, prcancel := context.WithTimeout(ctx, 30*time.Second) // 30 seconds timeout for each request
-			defer prcancel()
-			// Dynamic cutoff time based on the last commit to the PR
-			slogs.Logr.Info("Fetching last commit time", "PR", pr.GetHTMLURL())
-			lastCommitTime, err := getLastCommitTime(prctx, githubClient, owner, repo, pr.GetNumber())
+			pendingPR, err := PendingCIForPR(ctx, githubClient, cfg, approvalStore, teamMembers, owner, repo, pr)
 			if err != nil {
-				slogs.Logr.Error("Error retrieving last commit time", "PR", pr.GetNumber(), "repository", fullRepo.Name, "error", err)
+				slogs.Logr.Error("Error checking PR for pending CI", "PR", pr.GetNumber(), "repository", fullRepo.Name, "error", err)
 				continue
 			}
-			cutoffTime := lastCommitTime.Add(2 * time.Hour) // 2 hours after the last commit
-
-			if time.Now().Before(cutoffTime) {
-				slogs.Logr.Info("Skipping PR as it's still within the 2-hour window from the last commit", "PR", pr.GetNumber(), "repository", fullRepo.Name)
-				continue
+			if pendingPR != nil {
+				pendingPRs = append(pendingPRs, *pendingPR)
 			}
+		}
+	}
+	return pendingPRs, nil
+}
 
-			slogs.Logr.Info("Checking CI status for PR", "PR", pr.GetHTMLURL())
-			pendingCI, err := hasPendingCI(prctx, githubClient, owner, repo, pr.GetNumber())
-			if err != nil {
-				slogs.Logr.Error("Error checking CI status", "PR", pr.GetNumber(), "repository", fullRepo.Name, "error", err)
-				continue
-			}
+// PendingCIForPR auto-approves a community PR's workflow runs when its author is trusted, and otherwise checks whether the
+// PR is waiting for a maintainer to approve them. It returns nil when there is nothing to notify about.
+// Approvals are recorded in approvalStore, and a PR with any approved run is returned for announcing even if approving
+// a later run failed.
+func PendingCIForPR(ctx context.Context, githubClient *github.Client, cfg *config.Config, approvalStore database.ApprovalStore, teamMembers map[string]bool, owner, repo string, pr *github.PullRequest) (*PendingPR, error) {
+	fullRepo := owner + "/" + repo
+	pendingPR := &PendingPR{
+		Owner:     owner,
+		Repo:      repo,
+		PRNumber:  pr.GetNumber(),
+		URL:       pr.GetHTMLURL(),
+		Title:     pr.GetTitle(),
+		Author:    pr.GetUser().GetLogin(),
+		CreatedAt: pr.GetCreatedAt().Time,
+	}
 
-			slogs.Logr.Info("Checking team member activity for PR", "PR", pr.GetHTMLURL())
-			teamMemberActivity, err := checkTeamMemberActivity(prctx, githubClient, owner, repo, pr.GetNumber(), teamMembers, lastCommitTime)
-			if err != nil {
-				slogs.Logr.Error("Error checking team member activity", "PR", pr.GetNumber(), "repository", fullRepo.Name, "error", err)
-				continue // or handle the error as needed
-			}
+	reason, err := AutoApproveRuns(ctx, githubClient, cfg, approvalStore, owner, repo, pr)
+	if err != nil {
+		slogs.Logr.Error("Error auto-approving workflow runs", "PR", pr.GetNumber(), "repository", fullRepo, "error", err)
+	}
+	if reason != "" {
+		pendingPR.AutoApprovedReason = reason
+		return pendingPR, nil
+	}
 
-			slogs.Logr.Info("Evaluating PR", "PR", pr.GetHTMLURL(), "Action Required for CI", pendingCI, "teamMemberActivity", teamMemberActivity)
-			if pendingCI && !teamMemberActivity {
-				slogs.Logr.Info("PR is ready for CI checks approval", "PR", pr.GetNumber(), "repository", fullRepo.Name, "user", pr.User.GetLogin(), "created_at", pr.CreatedAt)
-				pendingPRs = append(pendingPRs, PendingPR{
-					Repo:     repo,
-					PRNumber: pr.GetNumber(),
-					URL:      pr.GetHTMLURL(),
-				})
-			} else {
-				slogs.Logr.Info("PR is not ready for CI approvals",
-					"PR", pr.GetNumber(),
-					"repository", fullRepo.Name)
-			}
+	pending, lastTeamActivity, err := IsPendingCI(ctx, githubClient, teamMembers, owner, repo, pr, cfg.PolicyFor(fullRepo).PendingCIGrace)
+	if err != nil {
+		return nil, err
+	}
+	if !pending {
+		return nil, nil
+	}
+	pendingPR.LastTeamActivity = lastTeamActivity
+	pendingPR.SensitiveFiles, err = FlagSensitiveFiles(ctx, githubClient, cfg, owner, repo, pr)
+	if err != nil {
+		slogs.Logr.Error("Error checking PR for sensitive files", "PR", pr.GetNumber(), "repository", fullRepo, "error", err)
+	}
+	return pendingPR, nil
+}
 
-		}
+// IsPendingCI reports whether a single community PR has workflows awaiting approval with no recent team member activity,
+// once grace has passed since its last commit. It also returns the last time a team member commented on or reviewed the PR.
+// Errors are logged before being returned so callers may simply skip the PR.
+func IsPendingCI(ctx context.Context, githubClient *github.Client, teamMembers map[string]bool, owner, repo string, pr *github.PullRequest, grace time.Duration) (bool, time.Time, error) {
+	fullRepo := fmt.Sprintf("%s/%s", owner, repo)
+	slogs.Logr.Info("Checking PR", "PR", pr.GetHTMLURL())
+	prctx, prcancel := context.WithTimeout(ctx, 30*time.Second) // 30 seconds timeout for each request
+	defer prcancel()
+	// Dynamic cutoff time based on the last commit to the PR
+	slogs.Logr.Info("Fetching last commit time", "PR", pr.GetHTMLURL())
+	lastCommitTime, err := getLastCommitTime(prctx, githubClient, owner, repo, pr.GetNumber())
+	if err != nil {
+		slogs.Logr.Error("Error retrieving last commit time", "PR", pr.GetNumber(), "repository", fullRepo, "error", err)
+		return false, time.Time{}, err
 	}
-	return pendingPRs, nil
+	cutoffTime := lastCommitTime.Add(grace)
+
+	if time.Now().Before(cutoffTime) {
+		slogs.Logr.Info("Skipping PR as it's still within the grace period from the last commit", "PR", pr.GetNumber(), "repository", fullRepo, "grace", grace.String())
+		return false, time.Time{}, nil
+	}
+
+	slogs.Logr.Info("Checking CI status for PR", "PR", pr.GetHTMLURL())
+	pendingCI, err := hasPendingCI(prctx, githubClient, owner, repo, pr.GetNumber())
+	if err != nil {
+		slogs.Logr.Error("Error checking CI status", "PR", pr.GetNumber(), "repository", fullRepo, "error", err)
+		return false, time.Time{}, err
+	}
+
+	slogs.Logr.Info("Checking team member activity for PR", "PR", pr.GetHTMLURL())
+	teamMemberActivity, lastTeamActivity, err := checkTeamMemberActivity(prctx, githubClient, owner, repo, pr.GetNumber(), teamMembers, lastCommitTime)
+	if err != nil {
+		slogs.Logr.Error("Error checking team member activity", "PR", pr.GetNumber(), "repository", fullRepo, "error", err)
+		return false, time.Time{}, err
+	}
+
+	slogs.Logr.Info("Evaluating PR", "PR", pr.GetHTMLURL(), "Action Required for CI", pendingCI, "teamMemberActivity", teamMemberActivity)
+	if pendingCI && !teamMemberActivity {
+		slogs.Logr.Info("PR is ready for CI checks approval", "PR", pr.GetNumber(), "repository", fullRepo, "user", pr.User.GetLogin(), "created_at", pr.CreatedAt)
+		return true, lastTeamActivity, nil
+	}
+
+	slogs.Logr.Info("PR is not ready for CI approvals",
+		"PR", pr.GetNumber(),
+		"repository", fullRepo)
+	return false, time.Time{}, nil
 }
 
 func getLastCommitTime(ctx context.Context, client *github.Client, owner, repo string, prNumber int) (time.Time, error) {
@@ -121,58 +184,81 @@ func hasPendingCI(ctx context.Context, client *github.Client, owner, repo string
 		return false, fmt.Errorf("failed to fetch pull request #%d: %w", prNumber, err)
 	}
 
-	headSHA := pr.GetHead().GetSHA()
+	runs, err := actionRequiredRuns(ctx, client, owner, repo, pr.GetHead().GetSHA())
+	if err != nil {
+		return false, err
+	}
+	if len(runs) > 0 {
+		slogs.Logr.Info("Workflow awaiting approval for", "PR", prNumber, "repository", repo)
+		return true, nil
+	}
+
+	return false, nil
+}
 
+// actionRequiredRuns returns the workflow runs for a commit that are waiting for a maintainer to approve them
+func actionRequiredRuns(ctx context.Context, client *github.Client, owner, repo string, headSHA string) ([]*github.WorkflowRun, error) {
 	opts := &github.ListWorkflowRunsOptions{
 		Status:  "action_required",
 		HeadSHA: headSHA,
 	}
 	workflowRuns, _, err := client.Actions.ListRepositoryWorkflowRuns(ctx, owner, repo, opts)
 	if err != nil {
-		return false, fmt.Errorf("failed to fetch workflow runs for repository %s/%s: %w", owner, repo, err)
+		return nil, fmt.Errorf("failed to fetch workflow runs for repository %s/%s: %w", owner, repo, err)
 	}
 
-	// Check for any workflows waiting for approval
-	for _, workflows := range workflowRuns.WorkflowRuns {
-		// This will check to see if there are any workflows that need approval and also ensure that its the same commit SHA as the PR we care about
-		if workflows.GetConclusion() == "action_required" && workflows.GetHeadSHA() == headSHA {
-			slogs.Logr.Info("Workflow awaiting approval for", "PR", prNumber, "repository", repo)
-			return true, nil
+	var runs []*github.WorkflowRun
+	for _, run := range workflowRuns.WorkflowRuns {
+		// Make sure the run needs approval and is for the same commit SHA as the PR we care about
+		if run.GetConclusion() == "action_required" && run.GetHeadSHA() == headSHA {
+			runs = append(runs, run)
 		}
 	}
-
-	return false, nil
+	return runs, nil
 }
 
-func checkTeamMemberActivity(ctx context.Context, client *github.Client, owner, repo string, prNumber int, teamMembers map[string]bool, lastCommitTime time.Time) (bool, error) {
+func checkTeamMemberActivity(ctx context.Context, client *github.Client, owner, repo string, prNumber int, teamMembers map[string]bool, lastCommitTime time.Time) (bool, time.Time, error) {
+	var lastActivity time.Time
 	comments, _, err := client.Issues.ListComments(ctx, owner, repo, prNumber, nil)
 	if err != nil {
-		return false, fmt.Errorf("failed to fetch comments: %w", err)
+		return false, time.Time{}, fmt.Errorf("failed to fetch comments: %w", err)
 	}
 
 	for _, comment := range comments {
 		slogs.Logr.Info("Checking comment from user", "user", comment.User.GetLogin(), "created_at", comment.CreatedAt.Format(time.RFC3339), "PR", prNumber, "repository", repo)
-		if _, ok := teamMembers[comment.User.GetLogin()]; ok && comment.CreatedAt.After(lastCommitTime) {
+		if !teamMembers[comment.User.GetLogin()] {
+			continue
+		}
+		if comment.CreatedAt.After(lastActivity) {
+			lastActivity = comment.CreatedAt.Time
+		}
+		if comment.CreatedAt.After(lastCommitTime) {
 			slogs.Logr.Info("Found team member comment after last commit time", "time", comment.CreatedAt.Format(time.RFC3339), "PR", prNumber, "repository", repo)
 			// Check if the comment is after the last commit
-			return true, nil // Active and relevant participation
+			return true, lastActivity, nil // Active and relevant participation
 		}
 	}
 
-	reviews, _, err := client.PullRequests.ListReviews(context.Background(), owner, repo, prNumber, nil)
+	reviews, _, err := client.PullRequests.ListReviews(ctx, owner, repo, prNumber, nil)
 	if err != nil {
-		return false, fmt.Errorf("failed to fetch reviews: %w for PR #%d of repo %s", err, prNumber, repo)
+		return false, time.Time{}, fmt.Errorf("failed to fetch reviews: %w for PR #%d of repo %s", err, prNumber, repo)
 	}
 
 	for _, review := range reviews {
-		if _, ok := teamMembers[review.User.GetLogin()]; ok && review.SubmittedAt.After(lastCommitTime) {
+		if !teamMembers[review.User.GetLogin()] {
+			continue
+		}
+		if review.SubmittedAt.After(lastActivity) {
+			lastActivity = review.SubmittedAt.Time
+		}
+		if review.SubmittedAt.After(lastCommitTime) {
 			switch review.GetState() {
 			case "DISMISSED", "CHANGES_REQUESTED", "COMMENTED":
 				// Check if the review is after the last commit and is in one of the specified states
-				return true, nil
+				return true, lastActivity, nil
 			}
 		}
 	}
 
-	return false, nil // No recent relevant activity from team members
+	return false, lastActivity, nil // No recent relevant activity from team members
 }
@@ -0,0 +1,127 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/google/go-github/v60/github"
+
+	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/templates"
+)
+
+// maxStatusDescription is the longest description GitHub accepts on a commit status
+const maxStatusDescription = 140
+
+// checkResult is the outcome of one of the bot's commit checks
+type checkResult struct {
+	// Name is the check run name, or the commit status context
+	Name   string
+	Passed bool
+	// Title is the one-line result, which is all a commit status can show
+	Title string
+	// Summary is the markdown shown on the check run
+	Summary string
+}
+
+// publishCheck publishes the result on a commit as a check run when the bot is authenticated as a GitHub App, since only apps
+// can create them, and as a commit status otherwise. Either can be made required in branch protection.
+func publishCheck(ctx context.Context, client *github.Client, cfg *config.Config, owner, repo, sha string, result checkResult) error {
+	if cfg.GithubApp.AppID == 0 {
+		state := "failure"
+		if result.Passed {
+			state = "success"
+		}
+		description := result.Title
+		if len(description) > maxStatusDescription {
+			description = description[:maxStatusDescription-3] + "..."
+		}
+		return setCommitStatus(ctx, client, owner, repo, sha, result.Name, state, description)
+	}
+	return setCheckRun(ctx, client, cfg.GithubApp.AppID, owner, repo, sha, result)
+}
+
+// setCheckRun creates the named check run on a commit, or updates the bot's existing one when the result has changed
+func setCheckRun(ctx context.Context, client *github.Client, appID int64, owner, repo, sha string, result checkResult) error {
+	conclusion := "failure"
+	if result.Passed {
+		conclusion = "success"
+	}
+	output := &github.CheckRunOutput{
+		Title:   github.String(result.Title),
+		Summary: github.String(result.Summary),
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	runs, _, err := client.Checks.ListCheckRunsForRef(reqCtx, owner, repo, sha, &github.ListCheckRunsOptions{
+		CheckName: github.String(result.Name),
+		AppID:     github.Int64(appID),
+		Filter:    github.String("latest"),
+	})
+	cancel()
+	if err != nil {
+		return fmt.Errorf("error listing check runs for %s: %w", sha, err)
+	}
+
+	if len(runs.CheckRuns) > 0 {
+		existing := runs.CheckRuns[0]
+		if existing.GetConclusion() == conclusion && existing.GetOutput().GetTitle() == result.Title && existing.GetOutput().GetSummary() == result.Summary {
+			return nil
+		}
+		slogs.Logr.Info("Updating check run", "repository", owner+"/"+repo, "sha", sha, "check", result.Name, "conclusion", conclusion)
+		reqCtx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		_, _, err = client.Checks.UpdateCheckRun(reqCtx, owner, repo, existing.GetID(), github.UpdateCheckRunOptions{
+			Name:        result.Name,
+			Status:      github.String("completed"),
+			Conclusion:  github.String(conclusion),
+			CompletedAt: &github.Timestamp{Time: time.Now()},
+			Output:      output,
+		})
+		if err != nil {
+			return fmt.Errorf("error updating %s check run on %s: %w", result.Name, sha, err)
+		}
+		return nil
+	}
+
+	slogs.Logr.Info("Creating check run", "repository", owner+"/"+repo, "sha", sha, "check", result.Name, "conclusion", conclusion)
+	reqCtx, cancel = context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	_, _, err = client.Checks.CreateCheckRun(reqCtx, owner, repo, github.CreateCheckRunOptions{
+		Name:        result.Name,
+		HeadSHA:     sha,
+		Status:      github.String("completed"),
+		Conclusion:  github.String(conclusion),
+		CompletedAt: &github.Timestamp{Time: time.Now()},
+		Output:      output,
+	})
+	if err != nil {
+		return fmt.Errorf("error creating %s check run on %s: %w", result.Name, sha, err)
+	}
+	return nil
+}
+
+// commitTable formats the failing commits as a markdown table
+func commitTable(commits []templates.Commit) string {
+	var table strings.Builder
+	table.WriteString("| Commit | Author | Reason |\n| --- | --- | --- |\n")
+	for _, commit := range commits {
+		author := commit.Author
+		if commit.Email != "" {
+			author = fmt.Sprintf("%s (%s)", commit.Author, commit.Email)
+		}
+		fmt.Fprintf(&table, "| `%s` | %s | `%s`: %s |\n", templates.ShortSHA(commit.SHA), templates.EscapeTableCell(author), commit.Reason, commit.Description)
+	}
+	return table.String()
+}
+
+// plural returns singular for one and plural otherwise
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
@@ -0,0 +1,137 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/google/go-github/v60/github"
+
+	"github.com/chia-network/github-bot/internal/config"
+)
+
+// StaleIssue holds information about a community issue with no recent team member activity
+type StaleIssue struct {
+	Owner       string
+	Repo        string
+	IssueNumber int
+	URL         string
+	Title       string
+	Author      string
+	// CreatedAt and LastTeamActivity are shown in digest messages. LastTeamActivity is zero if the team never interacted with the issue.
+	CreatedAt        time.Time
+	LastTeamActivity time.Time
+}
+
+// CheckStaleIssues returns the open issues opened by community members that have no team member activity within each repo's
+// stale_issues.stale_after window, leaving out issues with any of the repo's stale_issues.exclude_labels
+func CheckStaleIssues(ctx context.Context, githubClient *github.Client, cfg *config.Config, snapshot *Snapshot) ([]StaleIssue, error) {
+	var staleIssues []StaleIssue
+	teamMembers, err := snapshot.TeamMembers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fullRepo := range cfg.CheckRepos {
+		slogs.Logr.Info("Checking repository for stale issues", "repository", fullRepo.Name)
+		parts := strings.Split(fullRepo.Name, "/")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid repository name - must contain owner and repository: %s", fullRepo.Name)
+		}
+		owner, repo := parts[0], parts[1]
+		policy := cfg.PolicyFor(fullRepo.Name)
+		staleAfter := policy.StaleIssuesAfter()
+		cutoffDate := time.Now().Add(-staleAfter)
+
+		issues, err := listOpenIssues(ctx, githubClient, owner, repo, fullRepo.MinimumNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range issues {
+			author := issue.GetUser().GetLogin()
+			if teamMembers[author] || cfg.SkipUsersMap[author] {
+				continue
+			}
+			if excluded := excludedLabel(issue, policy.StaleIssues.ExcludeLabels); excluded != "" {
+				slogs.Logr.Info("Skipping issue with excluded label", "issue", issue.GetNumber(), "repository", fullRepo.Name, "label", excluded)
+				continue
+			}
+
+			stale, lastTeamActivity, err := isStale(ctx, githubClient, owner, repo, issue.GetNumber(), issue.GetCreatedAt().Time, teamMembers, cutoffDate)
+			if err != nil {
+				slogs.Logr.Error("Error checking if issue is stale", "issue", issue.GetNumber(), "repository", fullRepo.Name, "error", err)
+				continue
+			}
+			if stale {
+				slogs.Logr.Info("Issue has no team member activity within the stale window", "issue", issue.GetNumber(), "repository", fullRepo.Name, "window", staleAfter.String(), "user", author, "created_at", issue.CreatedAt)
+				staleIssues = append(staleIssues, newStaleIssue(owner, repo, issue, lastTeamActivity))
+			}
+		}
+	}
+
+	return staleIssues, nil
+}
+
+func newStaleIssue(owner, repo string, issue *github.Issue, lastTeamActivity time.Time) StaleIssue {
+	return StaleIssue{
+		Owner:            owner,
+		Repo:             repo,
+		IssueNumber:      issue.GetNumber(),
+		URL:              issue.GetHTMLURL(),
+		Title:            issue.GetTitle(),
+		Author:           issue.GetUser().GetLogin(),
+		CreatedAt:        issue.GetCreatedAt().Time,
+		LastTeamActivity: lastTeamActivity,
+	}
+}
+
+// listOpenIssues fetches every open issue in the repository numbered at or above minimumNumber, leaving out pull requests,
+// which the issues API also returns
+func listOpenIssues(ctx context.Context, githubClient *github.Client, owner string, repo string, minimumNumber int) ([]*github.Issue, error) {
+	var openIssues []*github.Issue
+	opts := &github.IssueListByRepoOptions{
+		State:       "open",
+		Sort:        "created",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	for {
+		issues, resp, err := githubClient.Issues.ListByRepo(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error listing issues for %s/%s: %w", owner, repo, err)
+		}
+
+		for _, issue := range issues {
+			if issue.GetNumber() < minimumNumber {
+				return openIssues, nil // Sorted newest first, so nothing further is above the minimum
+			}
+			if issue.IsPullRequest() {
+				continue
+			}
+			openIssues = append(openIssues, issue)
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return openIssues, nil
+}
+
+// excludedLabel returns the first of the issue's labels that is in exclude, compared case-insensitively, or "" if there is none
+func excludedLabel(issue *github.Issue, exclude []string) string {
+	for _, label := range issue.Labels {
+		for _, name := range exclude {
+			if strings.EqualFold(label.GetName(), name) {
+				return label.GetName()
+			}
+		}
+	}
+	return ""
+}
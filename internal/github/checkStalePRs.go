@@ -14,16 +14,21 @@ import (
 
 // StalePR holds information about pending PRs
 type StalePR struct {
+	Owner    string
 	Repo     string
 	PRNumber int
 	URL      string
+	Title    string
+	Author   string
+	// CreatedAt and LastTeamActivity are shown in digest messages. LastTeamActivity is zero if the team never interacted with the PR.
+	CreatedAt        time.Time
+	LastTeamActivity time.Time
 }
 
-// CheckStalePRs will return a list of PR URLs that have not been updated in the last 7 days by internal team members.
-func CheckStalePRs(ctx context.Context, githubClient *github.Client, cfg *config.Config) ([]StalePR, error) {
+// CheckStalePRs will return a list of PR URLs that have not been updated by internal team members within each repo's stale_after window.
+func CheckStalePRs(ctx context.Context, githubClient *github.Client, cfg *config.Config, snapshot *Snapshot) ([]StalePR, error) {
 	var stalePRs []StalePR
-	cutoffDate := time.Now().Add(-7 * 24 * time.Hour) // 7 days ago
-	teamMembers, err := GetTeamMemberList(githubClient, cfg.InternalTeam, cfg.InternalTeamIgnoredUsers)
+	teamMembers, err := snapshot.TeamMembers(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -35,8 +40,10 @@ func CheckStalePRs(ctx context.Context, githubClient *github.Client, cfg *config
 			return nil, fmt.Errorf("invalid repository name - must contain owner and repository: %s", fullRepo.Name)
 		}
 		owner, repo := parts[0], parts[1]
+		staleAfter := cfg.PolicyFor(fullRepo.Name).StaleAfter
+		cutoffDate := time.Now().Add(-staleAfter)
 
-		communityPRs, err := FindCommunityPRs(cfg, teamMembers, githubClient, owner, repo, fullRepo.MinimumNumber)
+		communityPRs, err := snapshot.CommunityPRs(ctx, owner, repo, fullRepo.MinimumNumber)
 		if err != nil {
 			return nil, err
 		}
@@ -44,17 +51,22 @@ func CheckStalePRs(ctx context.Context, githubClient *github.Client, cfg *config
 		for _, pr := range communityPRs {
 			repoName := pr.GetBase().GetRepo().GetFullName() // Get the full name of the repository
 			slogs.Logr.Info("Checking if PR is stale", "PR", pr.GetHTMLURL())
-			stale, err := isStale(ctx, githubClient, pr, teamMembers, cutoffDate) // Handle both returned values
+			stale, lastTeamActivity, err := isStale(ctx, githubClient, owner, repo, pr.GetNumber(), pr.GetCreatedAt().Time, teamMembers, cutoffDate) // Handle both returned values
 			if err != nil {
 				slogs.Logr.Error("Error checking if PR is stale", "repository", repoName, "error", err)
 				continue // Skip this PR or handle the error appropriately
 			}
 			if stale {
-				slogs.Logr.Info("PR has no team member activity within the last seven days", "PR", pr.GetNumber(), "repository", fullRepo.Name, "user", pr.User.GetLogin(), "created_at", pr.CreatedAt)
+				slogs.Logr.Info("PR has no team member activity within the stale window", "PR", pr.GetNumber(), "repository", fullRepo.Name, "window", staleAfter.String(), "user", pr.User.GetLogin(), "created_at", pr.CreatedAt)
 				stalePRs = append(stalePRs, StalePR{
-					Repo:     repo,
-					PRNumber: pr.GetNumber(),
-					URL:      pr.GetHTMLURL(),
+					Owner:            owner,
+					Repo:             repo,
+					PRNumber:         pr.GetNumber(),
+					URL:              pr.GetHTMLURL(),
+					Title:            pr.GetTitle(),
+					Author:           pr.GetUser().GetLogin(),
+					CreatedAt:        pr.GetCreatedAt().Time,
+					LastTeamActivity: lastTeamActivity,
 				})
 			} else {
 				slogs.Logr.Info("PR is not stale",
@@ -67,34 +79,39 @@ func CheckStalePRs(ctx context.Context, githubClient *github.Client, cfg *config
 	return stalePRs, nil
 }
 
-// Checks if a PR is stale based on the last update from team members
-func isStale(ctx context.Context, githubClient *github.Client, pr *github.PullRequest, teamMembers map[string]bool, cutoffDate time.Time) (bool, error) {
+// Checks if a PR or issue is stale based on the last update from team members, also returning when that update was
+func isStale(ctx context.Context, githubClient *github.Client, owner, repo string, number int, createdAt time.Time, teamMembers map[string]bool, cutoffDate time.Time) (bool, time.Time, error) {
 	listOptions := &github.ListOptions{PerPage: 100}
-	if pr.GetCreatedAt().After(cutoffDate) {
-		slogs.Logr.Info("PR was created within the last seven days, so it cannot be stale", "PR", pr.GetNumber(), "repository", pr.Base.Repo.GetName())
-		return false, nil
+	var lastTeamActivity time.Time
+	if createdAt.After(cutoffDate) {
+		slogs.Logr.Info("PR was created within the stale window, so it cannot be stale", "PR", number, "repository", repo)
+		return false, lastTeamActivity, nil
 	}
 	for {
 		staleCtx, staleCtxCancel := context.WithTimeout(ctx, 30*time.Second) // 30 seconds timeout for each request
-		events, resp, err := githubClient.Issues.ListIssueTimeline(staleCtx, pr.Base.Repo.Owner.GetLogin(), pr.Base.Repo.GetName(), pr.GetNumber(), listOptions)
+		events, resp, err := githubClient.Issues.ListIssueTimeline(staleCtx, owner, repo, number, listOptions)
 		staleCtxCancel()
 		if err != nil {
-			slogs.Logr.Error("Failed to get timeline for PR", "PR", pr.GetNumber(), "repository", pr.Base.Repo.GetName(), "error", err)
-			return false, err
+			slogs.Logr.Error("Failed to get timeline for PR", "PR", number, "repository", repo, "error", err)
+			return false, lastTeamActivity, err
 		}
 		for _, event := range events {
 			if event.Event == nil {
 				if event.ID != nil {
-					slogs.Logr.Warn("Event does not specify any of the known event types, nil event type. Cannot process event", "PR", pr.GetNumber(), "repository", pr.Base.Repo.GetName(), "event", *event.ID)
+					slogs.Logr.Warn("Event does not specify any of the known event types, nil event type. Cannot process event", "PR", number, "repository", repo, "event", *event.ID)
 				}
 				continue
 			}
 			eventTime := getEventTime(event)
-			if eventTime != nil && (*eventTime).After(cutoffDate) {
-				userLogin := getUserLogin(event)
-				if userLogin != "" && teamMembers[userLogin] {
-					return false, nil // Found a recent team member activity
-				}
+			userLogin := getUserLogin(event)
+			if eventTime == nil || userLogin == "" || !teamMembers[userLogin] {
+				continue
+			}
+			if eventTime.After(cutoffDate) {
+				return false, eventTime.Time, nil // Found a recent team member activity
+			}
+			if eventTime.After(lastTeamActivity) {
+				lastTeamActivity = eventTime.Time
 			}
 		}
 		if resp.NextPage == 0 {
@@ -102,7 +119,7 @@ func isStale(ctx context.Context, githubClient *github.Client, pr *github.PullRe
 		}
 		listOptions.Page = resp.NextPage
 	}
-	return true, nil
+	return true, lastTeamActivity, nil
 }
 
 func getUserLogin(event *github.Timeline) string {
@@ -10,28 +10,55 @@ import (
 	"github.com/google/go-github/v60/github"
 
 	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/templates"
 )
 
 const (
-	automationBotName     = "ChiaAutomation"
-	unsignedCommitMessage = "Your commits are not signed and our branch protection rules require signed commits. For more information on how to create signed commits, please visit this page: https://docs.github.com/en/authentication/managing-commit-signature-verification/about-commit-signature-verification. Please use the button towards the bottom of the page to close this pull request and open a new one with signed commits."
+	// signedCommitsCheckName is the check run or commit status the signature verification result is published as
+	signedCommitsCheckName = "github-bot/signed-commits"
+	// legacyUnsignedComment is the fixed text of unsigned commit comments posted before comments were tagged with markers
+	legacyUnsignedComment = "Your commits are not signed and our branch protection rules require signed commits. For more information on how to create signed commits, please visit this page: https://docs.github.com/en/authentication/managing-commit-signature-verification/about-commit-signature-verification. Please use the button towards the bottom of the page to close this pull request and open a new one with signed commits."
 )
 
+// verificationReasons explain GitHub's signature verification reasons to commit authors
+var verificationReasons = map[string]string{
+	"unsigned":               "the commit is not signed",
+	"unknown_key":            "the signing key is not on the author's GitHub account",
+	"expired_key":            "the signing key has expired",
+	"not_signing_key":        "the key is not allowed to sign commits",
+	"no_user":                "the author's email does not belong to a GitHub account",
+	"unverified_email":       "the author's email is not verified on their GitHub account",
+	"bad_email":              "the signing key does not include the author's email",
+	"unknown_signature_type": "the signature is not GPG, SSH or S/MIME",
+	"malformed_signature":    "the signature could not be parsed",
+	"invalid":                "the signature does not match the commit",
+	"gpgverify_error":        "GitHub could not verify the signature",
+	"gpgverify_unavailable":  "GitHub could not verify the signature",
+}
+
+// signedCommitsRemediation is shown on a failing signed commits check
+const signedCommitsRemediation = `### How to fix
+
+1. Set up commit signing with a GPG, SSH or S/MIME key: https://docs.github.com/en/authentication/managing-commit-signature-verification/signing-commits
+2. Add the key to your GitHub account, and commit with an email address that is verified on the account.
+3. Re-sign the commits, for example with ` + "`git rebase --exec 'git commit --amend --no-edit --no-verify -S' <base branch>`" + `, and force push the branch.`
+
 // UnsignedPRs holds information about pending PRs
 type UnsignedPRs struct {
-	Owner    string
-	Repo     string
-	PRNumber int
-	URL      string
+	Owner     string
+	Repo      string
+	PRNumber  int
+	URL       string
+	Title     string
+	Author    string
+	CreatedAt time.Time
+	// Commits are the commits that failed signature verification
+	Commits []templates.Commit
 }
 
 // CheckUnsignedCommits will return a list of PR URLs that have not been updated in the last 7 days by internal team members.
-func CheckUnsignedCommits(ctx context.Context, githubClient *github.Client, cfg *config.Config) ([]UnsignedPRs, error) {
+func CheckUnsignedCommits(ctx context.Context, githubClient *github.Client, cfg *config.Config, snapshot *Snapshot) ([]UnsignedPRs, error) {
 	var unsignedPRs []UnsignedPRs
-	teamMembers, err := GetTeamMemberList(githubClient, cfg.InternalTeam, cfg.InternalTeamIgnoredUsers)
-	if err != nil {
-		return nil, err
-	}
 
 	for _, fullRepo := range cfg.CheckRepos {
 		slogs.Logr.Info("Checking repository", "repository", fullRepo.Name)
@@ -41,7 +68,7 @@ func CheckUnsignedCommits(ctx context.Context, githubClient *github.Client, cfg
 		}
 		owner, repo := parts[0], parts[1]
 
-		communityPRs, err := FindAllPRs(cfg, teamMembers, githubClient, owner, repo, fullRepo.MinimumNumber)
+		communityPRs, err := snapshot.AllPRs(ctx, owner, repo, fullRepo.MinimumNumber)
 		if err != nil {
 			return nil, err
 		}
@@ -49,25 +76,31 @@ func CheckUnsignedCommits(ctx context.Context, githubClient *github.Client, cfg
 		for _, pr := range communityPRs {
 			repoName := pr.GetBase().GetRepo().GetFullName() // Get the full name of the repository
 			slogs.Logr.Info("Checking if PR has unsigned commits", "PR", pr.GetHTMLURL())
-			unsigned, err := hasUnsignedCommits(ctx, githubClient, pr, teamMembers) // Handle both returned values
+			prCommits, err := listCommits(ctx, githubClient, pr)
 			if err != nil {
 				slogs.Logr.Error("Error checking if PR has unsigned commits", "repository", repoName, "error", err)
 				continue // Skip this PR or handle the error appropriately
 			}
-			if unsigned {
+			if cfg.PolicyFor(repoName).SignoffRequired() {
+				err = checkSignoff(ctx, githubClient, cfg, pr, prCommits)
+				if err != nil {
+					slogs.Logr.Error("Error checking commit sign-offs", "PR", pr.GetNumber(), "repository", repoName, "error", err)
+				}
+			}
+			commits := unsignedCommits(prCommits)
+			err = publishSignatureCheck(ctx, githubClient, cfg, pr, len(prCommits), commits)
+			if err != nil {
+				slogs.Logr.Error("Error publishing signed commits check", "PR", pr.GetNumber(), "repository", repoName, "error", err)
+			}
+			if len(commits) > 0 {
 				slogs.Logr.Info("PR has unsigned commits", "PR", pr.GetNumber(), "repository", fullRepo.Name, "user", pr.User.GetLogin(), "created_at", pr.CreatedAt)
-				unsignedPRs = append(unsignedPRs, UnsignedPRs{
-					Owner:    owner,
-					Repo:     repo,
-					PRNumber: pr.GetNumber(),
-					URL:      pr.GetHTMLURL(),
-				})
+				unsignedPRs = append(unsignedPRs, newUnsignedPR(owner, repo, pr, commits))
 
 			} else {
 				slogs.Logr.Info("No commits are unsigned",
 					"PR", pr.GetNumber(),
 					"repository", fullRepo.Name)
-				err = checkAndRemoveComment(ctx, githubClient, owner, repo, pr.GetNumber())
+				err = DeleteComment(ctx, githubClient, owner, repo, pr.GetNumber(), UnsignedCommentKey)
 				if err != nil {
 					slogs.Logr.Error("Error checking and removing comment", "repository", repoName, "error", err)
 					return nil, err
@@ -79,92 +112,127 @@ func CheckUnsignedCommits(ctx context.Context, githubClient *github.Client, cfg
 	return unsignedPRs, nil
 }
 
-// Checks if a PR has unsigned commits based on the last update from team members
-func hasUnsignedCommits(ctx context.Context, githubClient *github.Client, pr *github.PullRequest, teamMembers map[string]bool) (bool, error) {
+// CheckPRUnsignedCommits comments on a single PR when it has unsigned commits, or removes the earlier comment once every commit is signed.
+// Sign-offs are checked too when the repo requires them.
+func CheckPRUnsignedCommits(ctx context.Context, githubClient *github.Client, cfg *config.Config, owner, repo string, pr *github.PullRequest) error {
+	slogs.Logr.Info("Checking if PR has unsigned commits", "PR", pr.GetHTMLURL())
+	prCommits, err := listCommits(ctx, githubClient, pr)
+	if err != nil {
+		return err
+	}
+	if cfg.PolicyFor(owner + "/" + repo).SignoffRequired() {
+		err = checkSignoff(ctx, githubClient, cfg, pr, prCommits)
+		if err != nil {
+			return err
+		}
+	}
+	commits := unsignedCommits(prCommits)
+	err = publishSignatureCheck(ctx, githubClient, cfg, pr, len(prCommits), commits)
+	if err != nil {
+		return err
+	}
+	if len(commits) > 0 {
+		return CheckAndComment(ctx, githubClient, cfg, newUnsignedPR(owner, repo, pr, commits))
+	}
+	return DeleteComment(ctx, githubClient, owner, repo, pr.GetNumber(), UnsignedCommentKey)
+}
+
+func newUnsignedPR(owner, repo string, pr *github.PullRequest, commits []templates.Commit) UnsignedPRs {
+	return UnsignedPRs{
+		Owner:     owner,
+		Repo:      repo,
+		PRNumber:  pr.GetNumber(),
+		URL:       pr.GetHTMLURL(),
+		Title:     pr.GetTitle(),
+		Author:    pr.GetUser().GetLogin(),
+		CreatedAt: pr.GetCreatedAt().Time,
+		Commits:   commits,
+	}
+}
+
+// listCommits returns every commit in the PR
+func listCommits(ctx context.Context, githubClient *github.Client, pr *github.PullRequest) ([]*github.RepositoryCommit, error) {
+	var all []*github.RepositoryCommit
 	listOptions := &github.ListOptions{PerPage: 100}
 	for {
 		// Create a context for each request
-		unsignedCtx, unsignedCancel := context.WithTimeout(ctx, 30*time.Second) // 30 seconds timeout for each request
-		defer unsignedCancel()
-		commits, resp, err := githubClient.PullRequests.ListCommits(unsignedCtx, pr.Base.Repo.Owner.GetLogin(), pr.Base.Repo.GetName(), pr.GetNumber(), listOptions)
+		commitsCtx, commitsCancel := context.WithTimeout(ctx, 30*time.Second) // 30 seconds timeout for each request
+		commits, resp, err := githubClient.PullRequests.ListCommits(commitsCtx, pr.Base.Repo.Owner.GetLogin(), pr.Base.Repo.GetName(), pr.GetNumber(), listOptions)
+		commitsCancel()
 		if err != nil {
 			slogs.Logr.Error("Failed to get commits for PR", "PR", pr.GetNumber(), "repository", pr.Base.Repo.GetName(), "error", err)
-			return false, err
-		}
-		// Check each commit to see if it is signed
-		for _, commit := range commits {
-			if commit == nil || commit.Commit == nil {
-				continue
-			}
-			verification := commit.Commit.Verification
-			if verification == nil {
-				slogs.Logr.Info("Commit has no verification field", "commit_sha", commit.GetSHA(), "author", commit.Commit.Author.GetName(), "email", commit.Commit.Author.GetEmail())
-				return true, nil // Log and flag if no verification field
-			} else if !*verification.Verified {
-				slogs.Logr.Info("Commit is not verified", "commit_sha", commit.GetSHA(), "author", commit.Commit.Author.GetName(), "email", commit.Commit.Author.GetEmail(), "reason", verification.GetReason())
-				return true, nil // Log and flag if the commit is not verified
-			}
+			return nil, err
 		}
-		unsignedCancel() // Clean up the context at the end of the loop iteration
+		all = append(all, commits...)
 		if resp.NextPage == 0 {
-			break
+			return all, nil
 		}
 		listOptions.Page = resp.NextPage
 	}
-	return false, nil
 }
 
-// CheckAndComment checks for the specific comment from a specific account and posts a comment if it doesn't exist.
-func CheckAndComment(ctx context.Context, client *github.Client, owner, repo string, prNumber int) error {
-	comments, _, err := client.Issues.ListComments(ctx, owner, repo, prNumber, nil)
-	if err != nil {
-		return fmt.Errorf("error fetching comments: %v", err)
+// unsignedCommits returns every commit that fails signature verification
+func unsignedCommits(commits []*github.RepositoryCommit) []templates.Commit {
+	var unsigned []templates.Commit
+	// Check each commit to see if it is signed
+	for _, commit := range commits {
+		if commit == nil || commit.Commit == nil {
+			continue
+		}
+		verification := commit.Commit.Verification
+		failed := templates.Commit{SHA: commit.GetSHA(), Author: commit.Commit.Author.GetName(), Email: commit.Commit.Author.GetEmail()}
+		if verification == nil {
+			slogs.Logr.Info("Commit has no verification field", "commit_sha", commit.GetSHA(), "author", commit.Commit.Author.GetName(), "email", commit.Commit.Author.GetEmail())
+			failed.Reason = "unsigned"
+		} else if !verification.GetVerified() {
+			slogs.Logr.Info("Commit is not verified", "commit_sha", commit.GetSHA(), "author", commit.Commit.Author.GetName(), "email", commit.Commit.Author.GetEmail(), "reason", verification.GetReason())
+			failed.Reason = verification.GetReason()
+		} else {
+			continue
+		}
+		failed.Description = describeVerification(failed.Reason)
+		unsigned = append(unsigned, failed)
 	}
+	return unsigned
+}
 
-	// Check if the comment already exists
-	for _, comment := range comments {
-		if comment.GetUser().GetLogin() == automationBotName && strings.EqualFold(comment.GetBody(), unsignedCommitMessage) {
-			slogs.Logr.Info("Unsigned commit comment already exists", "repo", repo, "PR", prNumber)
-			return nil
-		}
+// describeVerification explains a verification reason, falling back to a generic explanation for reasons GitHub adds later
+func describeVerification(reason string) string {
+	if description, ok := verificationReasons[reason]; ok {
+		return description
 	}
+	return "the signature could not be verified"
+}
 
-	// Post the comment if it doesn't exist
-	comment := &github.IssueComment{
-		Body: github.String(unsignedCommitMessage),
+// publishSignatureCheck publishes whether every commit in the PR has a verified signature, listing the ones that don't,
+// when the repo has signed_commits_check turned on
+func publishSignatureCheck(ctx context.Context, githubClient *github.Client, cfg *config.Config, pr *github.PullRequest, total int, unsigned []templates.Commit) error {
+	owner, repo := pr.GetBase().GetRepo().GetOwner().GetLogin(), pr.GetBase().GetRepo().GetName()
+	if !cfg.PolicyFor(owner + "/" + repo).SignedCommitsChecked() {
+		return nil
 	}
-	slogs.Logr.Info("Creating comment for unsigned commits", "repo", repo, "PR", prNumber)
-	_, _, err = client.Issues.CreateComment(ctx, owner, repo, prNumber, comment)
-	if err != nil {
-		return fmt.Errorf("error creating comment: %v", err)
+	result := checkResult{Name: signedCommitsCheckName, Passed: len(unsigned) == 0}
+	if result.Passed {
+		result.Title = "All commits have verified signatures"
+		result.Summary = fmt.Sprintf("All %d %s in this pull request %s verified signatures.", total, plural(total, "commit", "commits"), plural(total, "has", "have"))
+	} else {
+		result.Title = fmt.Sprintf("%d of %d %s not have a verified signature", len(unsigned), total, plural(len(unsigned), "commit does", "commits do"))
+		result.Summary = "Branch protection requires signed commits.\n\n" + commitTable(unsigned) + "\n" + signedCommitsRemediation
 	}
-
-	return nil
+	return publishCheck(ctx, githubClient, cfg, owner, repo, pr.GetHead().GetSHA(), result)
 }
 
-func checkAndRemoveComment(ctx context.Context, client *github.Client, owner, repo string, prNumber int) error {
-	comments, _, err := client.Issues.ListComments(ctx, owner, repo, prNumber, nil)
-	if err != nil {
-		return fmt.Errorf("error fetching comments: %v", err)
+// CheckAndComment posts the repo's unsigned_comment, listing the failing commits, or edits the bot's earlier comment
+// when the failing commits have changed since it was posted.
+// Repos with signed_commits_check report unsigned commits in the check instead, so any earlier comment is removed.
+func CheckAndComment(ctx context.Context, client *github.Client, cfg *config.Config, pr UnsignedPRs) error {
+	policy := cfg.PolicyFor(pr.Owner + "/" + pr.Repo)
+	if policy.SignedCommitsChecked() {
+		return DeleteComment(ctx, client, pr.Owner, pr.Repo, pr.PRNumber, UnsignedCommentKey)
 	}
-
-	// Check if the comment exists
-	for _, comment := range comments {
-		if comment.GetUser().GetLogin() == automationBotName && strings.EqualFold(comment.GetBody(), unsignedCommitMessage) {
-			commentID := comment.GetID()
-			slogs.Logr.Info("Found unsigned commit comment to remove",
-				"comment_id", commentID,
-				"author", automationBotName,
-				"pr_number", prNumber)
-			_, err := client.Issues.DeleteComment(ctx, owner, repo, commentID)
-			if err != nil {
-				return fmt.Errorf("error deleting comment %d: %w", commentID, err)
-			}
-			slogs.Logr.Info("Successfully removed unsigned commit comment",
-				"comment_id", commentID,
-				"pr_number", prNumber)
-			return nil
-		}
+	text, err := templates.Render(templates.UnsignedComment, policy.Templates.UnsignedComment, pr.TemplateData(cfg))
+	if err != nil {
+		return err
 	}
-	return nil
+	return UpsertComment(ctx, client, pr.Owner, pr.Repo, pr.PRNumber, UnsignedCommentKey, text)
 }
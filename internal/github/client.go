@@ -0,0 +1,77 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/google/go-github/v60/github"
+
+	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/dryrun"
+)
+
+// botLogin is the login the bot's own comments are posted under, discovered by NewClient
+var botLogin string
+
+// NewClient builds the GitHub client for the configured credentials.
+// When github_app is configured, requests are authenticated with installation tokens, otherwise github_token is used.
+// With a recorder, anything that would change GitHub is recorded instead of sent.
+func NewClient(cfg *config.Config, recorder *dryrun.Recorder) (*github.Client, error) {
+	if cfg.GithubApp.AppID == 0 {
+		if cfg.GithubToken == "" {
+			return nil, fmt.Errorf("either github_token or github_app must be configured")
+		}
+		transport := withDryRun(newRateLimitTransport(http.DefaultTransport), recorder)
+		client := github.NewClient(&http.Client{Transport: transport}).WithAuthToken(cfg.GithubToken)
+
+		user, _, err := client.Users.Get(context.Background(), "")
+		if err != nil {
+			return nil, fmt.Errorf("error getting the authenticated user: %w", err)
+		}
+		botLogin = user.GetLogin()
+		slogs.Logr.Info("Authenticating as user", "user", botLogin)
+		return client, nil
+	}
+
+	// Rate limiting sits below the app transport so minting installation tokens is covered too
+	transport, err := newAppTransport(cfg.GithubApp, newRateLimitTransport(http.DefaultTransport))
+	if err != nil {
+		return nil, err
+	}
+
+	// Anything that can't be tied to an owner, e.g. search, goes through the internal team's org installation
+	teamOrg, _, _ := strings.Cut(cfg.InternalTeam, "/")
+	transport.defaultOwner = teamOrg
+
+	ctx := context.Background()
+	app, _, err := transport.appClient.Apps.Get(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("error getting GitHub App %d: %w", cfg.GithubApp.AppID, err)
+	}
+	// Installation tokens can't read /user, and comments made with them are attributed to the app's bot user
+	botLogin = fmt.Sprintf("%s[bot]", app.GetSlug())
+	slogs.Logr.Info("Authenticating as GitHub App", "app", app.GetSlug(), "bot", botLogin)
+
+	for _, repo := range cfg.CheckRepos {
+		owner, _, _ := strings.Cut(repo.Name, "/")
+		installationID, err := transport.installationFor(ctx, owner)
+		if err != nil {
+			slogs.Logr.Error("GitHub App is not installed for repository", "repository", repo.Name, "error", err)
+			continue
+		}
+		slogs.Logr.Info("Routing repository through installation", "repository", repo.Name, "installation", installationID)
+	}
+
+	// Dry-run wraps the app transport rather than sitting below it, so installation tokens are still minted
+	return github.NewClient(&http.Client{Transport: withDryRun(transport, recorder)}), nil
+}
+
+func withDryRun(transport http.RoundTripper, recorder *dryrun.Recorder) http.RoundTripper {
+	if recorder == nil {
+		return transport
+	}
+	return &dryrun.Transport{Recorder: recorder, Base: transport}
+}
@@ -0,0 +1,114 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/google/go-github/v60/github"
+)
+
+// codeownersPaths are where GitHub looks for a CODEOWNERS file, in the order it checks them
+var codeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// CodeOwners maps paths to their owners the way GitHub reads a CODEOWNERS file
+type CodeOwners struct {
+	rules []codeownersRule
+}
+
+type codeownersRule struct {
+	globs  []string
+	owners []string
+}
+
+// GetCodeOwners fetches and parses the repo's CODEOWNERS file at ref, returning nil if the repo has none
+func GetCodeOwners(ctx context.Context, client *github.Client, owner, repo, ref string) (*CodeOwners, error) {
+	for _, path := range codeownersPaths {
+		reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		file, _, resp, err := client.Repositories.GetContents(reqCtx, owner, repo, path, &github.RepositoryContentGetOptions{Ref: ref})
+		cancel()
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error fetching %s from %s/%s: %w", path, owner, repo, err)
+		}
+		if file == nil {
+			continue // A directory with the same name
+		}
+		content, err := file.GetContent()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding %s from %s/%s: %w", path, owner, repo, err)
+		}
+		return ParseCodeOwners(content), nil
+	}
+	return nil, nil
+}
+
+// ParseCodeOwners parses the contents of a CODEOWNERS file. Lines that can't be understood are skipped, as GitHub does.
+func ParseCodeOwners(content string) *CodeOwners {
+	codeOwners := &CodeOwners{}
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		globs := codeownersGlobs(fields[0])
+		if globs == nil {
+			continue
+		}
+		// A pattern with no owners is valid and removes ownership from the paths it matches
+		codeOwners.rules = append(codeOwners.rules, codeownersRule{globs: globs, owners: fields[1:]})
+	}
+	return codeOwners
+}
+
+// codeownersGlobs converts a gitignore-style CODEOWNERS pattern into doublestar globs, or nil if it is not valid
+func codeownersGlobs(pattern string) []string {
+	// A slash anywhere but the end anchors the pattern to the repo root; otherwise it matches at any depth
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	directory := strings.HasSuffix(pattern, "/")
+	glob := strings.TrimSuffix(strings.TrimPrefix(pattern, "/"), "/")
+	if glob == "" {
+		return nil
+	}
+	if !anchored {
+		glob = "**/" + glob
+	}
+
+	// Patterns match the directories they name as well as files, so everything under a matched directory is owned too
+	var globs []string
+	switch {
+	case directory:
+		globs = []string{glob + "/**"}
+	case strings.HasSuffix(glob, "/*"):
+		// docs/* only matches the directory's own files, not those in subdirectories
+		globs = []string{glob}
+	default:
+		globs = []string{glob, glob + "/**"}
+	}
+	for _, g := range globs {
+		if !doublestar.ValidatePattern(g) {
+			return nil
+		}
+	}
+	return globs
+}
+
+// Owners returns the owners of path from the last matching rule, which is how GitHub resolves overlapping rules
+func (c *CodeOwners) Owners(path string) []string {
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		for _, glob := range c.rules[i].globs {
+			if ok, _ := doublestar.Match(glob, path); ok {
+				return c.rules[i].owners
+			}
+		}
+	}
+	return nil
+}
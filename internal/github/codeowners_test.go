@@ -0,0 +1,79 @@
+package github
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCodeownersGlobs(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"*.go", []string{"**/*.go", "**/*.go/**"}},
+		{"docs", []string{"**/docs", "**/docs/**"}},
+		{"/docs", []string{"docs", "docs/**"}},
+		{"docs/", []string{"**/docs/**"}},
+		{"/docs/", []string{"docs/**"}},
+		{"docs/*", []string{"docs/*"}},
+		{"/docs/*", []string{"docs/*"}},
+		{"src/lib", []string{"src/lib", "src/lib/**"}},
+		{"**/build", []string{"**/build", "**/build/**"}},
+		{"/", nil},
+		{"[", nil},
+	}
+	for _, test := range tests {
+		got := codeownersGlobs(test.pattern)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("codeownersGlobs(%q) = %q, want %q", test.pattern, got, test.want)
+		}
+	}
+}
+
+func TestParseCodeOwners(t *testing.T) {
+	content := strings.Join([]string{
+		"# Default owners",
+		"*                @org/core",
+		"*.go             @gopher # trailing comment",
+		"/docs/           @writer",
+		"docs/*           @docs-lead",
+		"build            @builder",
+		"/scripts/*.sh    @ops alice@example.com",
+		"/vendor/",
+		"[                @nobody",
+		"",
+	}, "\n")
+	codeOwners := ParseCodeOwners(content)
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		// Only the catch-all matches
+		{"README.md", []string{"@org/core"}},
+		// The last matching rule wins over earlier ones
+		{"main.go", []string{"@gopher"}},
+		{"cmd/root.go", []string{"@gopher"}},
+		// docs/* only matches files directly in docs, so /docs/ still owns the subdirectories
+		{"docs/index.md", []string{"@docs-lead"}},
+		{"docs/api/index.md", []string{"@writer"}},
+		// /docs/ is anchored to the root, so nested docs directories fall through to earlier rules
+		{"src/docs/index.md", []string{"@org/core"}},
+		// A bare name matches a file or directory of that name at any depth
+		{"build", []string{"@builder"}},
+		{"tools/build/out.txt", []string{"@builder"}},
+		{"tools/builder.txt", []string{"@org/core"}},
+		// Email owners are kept as written
+		{"scripts/release.sh", []string{"@ops", "alice@example.com"}},
+		{"scripts/nested/release.sh", []string{"@org/core"}},
+		// A pattern without owners removes ownership
+		{"vendor/lib/lib.go", []string{}},
+	}
+	for _, test := range tests {
+		got := codeOwners.Owners(test.path)
+		if len(got) != len(test.want) || (len(got) > 0 && !reflect.DeepEqual(got, test.want)) {
+			t.Errorf("Owners(%q) = %q, want %q", test.path, got, test.want)
+		}
+	}
+}
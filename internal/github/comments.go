@@ -0,0 +1,121 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/google/go-github/v60/github"
+)
+
+// CommentKey identifies one kind of bot comment on a PR. Each comment is tagged with a hidden HTML marker built from its key,
+// so the bot finds its own comments however their templated text changes.
+type CommentKey string
+
+// Keys of the comments the bot posts
+const (
+	UnsignedCommentKey CommentKey = "unsigned"
+	SignoffCommentKey  CommentKey = "signoff"
+	WelcomeCommentKey  CommentKey = "welcome"
+	OversizeCommentKey CommentKey = "oversize"
+)
+
+// legacyComments are the texts of comments posted before they were tagged with markers, so they are still found
+var legacyComments = map[CommentKey]string{
+	UnsignedCommentKey: legacyUnsignedComment,
+}
+
+// Marker is the hidden HTML comment appended to the bot's comments with this key
+func (k CommentKey) Marker() string {
+	return fmt.Sprintf("<!-- github-bot:%s -->", k)
+}
+
+// CommentOnce posts body on a PR, tagged with the key's marker, unless the bot already posted a comment with that key.
+// An existing comment is left as it is, even if body has changed since it was posted.
+func CommentOnce(ctx context.Context, client *github.Client, owner, repo string, prNumber int, key CommentKey, body string) error {
+	existing, err := findComment(ctx, client, owner, repo, prNumber, key)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		slogs.Logr.Info("Comment already posted", "repo", repo, "PR", prNumber, "key", key)
+		return nil
+	}
+	return upsertComment(ctx, client, owner, repo, prNumber, nil, key, body)
+}
+
+// UpsertComment posts body on a PR, tagged with the key's marker, or edits the bot's earlier comment with that key when body
+// has changed, so the comment never goes stale
+func UpsertComment(ctx context.Context, client *github.Client, owner, repo string, prNumber int, key CommentKey, body string) error {
+	existing, err := findComment(ctx, client, owner, repo, prNumber, key)
+	if err != nil {
+		return err
+	}
+	return upsertComment(ctx, client, owner, repo, prNumber, existing, key, body)
+}
+
+// upsertComment posts body tagged with the key's marker, or edits existing to it when existing isn't nil
+func upsertComment(ctx context.Context, client *github.Client, owner, repo string, prNumber int, existing *github.IssueComment, key CommentKey, body string) error {
+	body = body + "\n\n" + key.Marker()
+	if existing == nil {
+		slogs.Logr.Info("Posting comment", "repo", repo, "PR", prNumber, "key", key)
+		_, _, err := client.Issues.CreateComment(ctx, owner, repo, prNumber, &github.IssueComment{Body: github.String(body)})
+		if err != nil {
+			return fmt.Errorf("error creating comment: %w", err)
+		}
+		return nil
+	}
+	if existing.GetBody() == body {
+		slogs.Logr.Info("Comment is up to date", "repo", repo, "PR", prNumber, "key", key)
+		return nil
+	}
+
+	slogs.Logr.Info("Updating comment", "repo", repo, "PR", prNumber, "key", key, "comment_id", existing.GetID())
+	_, _, err := client.Issues.EditComment(ctx, owner, repo, existing.GetID(), &github.IssueComment{Body: github.String(body)})
+	if err != nil {
+		return fmt.Errorf("error editing comment %d: %w", existing.GetID(), err)
+	}
+	return nil
+}
+
+// DeleteComment deletes the bot's comment with the key from a PR, if it posted one
+func DeleteComment(ctx context.Context, client *github.Client, owner, repo string, prNumber int, key CommentKey) error {
+	existing, err := findComment(ctx, client, owner, repo, prNumber, key)
+	if err != nil || existing == nil {
+		return err
+	}
+
+	slogs.Logr.Info("Deleting comment", "repo", repo, "PR", prNumber, "key", key, "comment_id", existing.GetID())
+	_, err = client.Issues.DeleteComment(ctx, owner, repo, existing.GetID())
+	if err != nil {
+		return fmt.Errorf("error deleting comment %d: %w", existing.GetID(), err)
+	}
+	return nil
+}
+
+// findComment returns the bot's comment with the key on a PR, or nil if there is none
+func findComment(ctx context.Context, client *github.Client, owner, repo string, prNumber int, key CommentKey) (*github.IssueComment, error) {
+	marker := key.Marker()
+	legacy, hasLegacy := legacyComments[key]
+
+	listOptions := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := client.Issues.ListComments(ctx, owner, repo, prNumber, listOptions)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching comments: %w", err)
+		}
+		for _, comment := range comments {
+			if comment.GetUser().GetLogin() != botLogin {
+				continue
+			}
+			if strings.Contains(comment.GetBody(), marker) || (hasLegacy && strings.EqualFold(comment.GetBody(), legacy)) {
+				return comment, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return nil, nil
+		}
+		listOptions.Page = resp.NextPage
+	}
+}
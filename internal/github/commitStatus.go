@@ -0,0 +1,44 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/google/go-github/v60/github"
+)
+
+// setCommitStatus sets the status for statusContext on a commit, unless its latest status already has the same state and description.
+// GitHub keeps every status posted, and caps them per commit and context, so repeated checks of an unchanged commit don't post again.
+func setCommitStatus(ctx context.Context, client *github.Client, owner, repo, sha, statusContext, state, description string) error {
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	statuses, _, err := client.Repositories.ListStatuses(reqCtx, owner, repo, sha, &github.ListOptions{PerPage: 100})
+	cancel()
+	if err != nil {
+		return fmt.Errorf("error listing statuses for %s: %w", sha, err)
+	}
+	// Statuses are listed newest first
+	for _, status := range statuses {
+		if status.GetContext() != statusContext {
+			continue
+		}
+		if status.GetState() == state && status.GetDescription() == description {
+			return nil
+		}
+		break
+	}
+
+	slogs.Logr.Info("Setting commit status", "repository", owner+"/"+repo, "sha", sha, "context", statusContext, "state", state)
+	reqCtx, cancel = context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	_, _, err = client.Repositories.CreateStatus(reqCtx, owner, repo, sha, &github.RepoStatus{
+		State:       github.String(state),
+		Description: github.String(description),
+		Context:     github.String(statusContext),
+	})
+	if err != nil {
+		return fmt.Errorf("error setting %s status on %s: %w", statusContext, sha, err)
+	}
+	return nil
+}
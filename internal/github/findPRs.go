@@ -10,19 +10,9 @@ import (
 	"github.com/chia-network/github-bot/internal/config"
 )
 
-// FindCommunityPRs obtains PRs based on provided filters for community members
-func FindCommunityPRs(cfg *config.Config, teamMembers map[string]bool, githubClient *github.Client, owner string, repo string, minimumNumber int) ([]*github.PullRequest, error) {
-	return findPRs(cfg, teamMembers, githubClient, owner, repo, minimumNumber, true)
-}
-
-// FindAllPRs obtains all PRs for the repository
-func FindAllPRs(cfg *config.Config, teamMembers map[string]bool, githubClient *github.Client, owner string, repo string, minimumNumber int) ([]*github.PullRequest, error) {
-	return findPRs(cfg, teamMembers, githubClient, owner, repo, minimumNumber, false)
-}
-
-// findPRs handles fetching and filtering PRs based on community or all contributors
-func findPRs(cfg *config.Config, teamMembers map[string]bool, githubClient *github.Client, owner string, repo string, minimumNumber int, filterCommunity bool) ([]*github.PullRequest, error) {
-	var finalPRs []*github.PullRequest
+// listOpenPRs fetches every open PR in the repository numbered at or above minimumNumber, without any filtering by author
+func listOpenPRs(ctx context.Context, githubClient *github.Client, owner string, repo string, minimumNumber int) ([]*github.PullRequest, error) {
+	var openPRs []*github.PullRequest
 	opts := &github.PullRequestListOptions{
 		State:     "open",
 		Sort:      "created",
@@ -34,29 +24,16 @@ func findPRs(cfg *config.Config, teamMembers map[string]bool, githubClient *gith
 	}
 
 	for {
-		pullRequests, resp, err := githubClient.PullRequests.List(context.Background(), owner, repo, opts)
+		pullRequests, resp, err := githubClient.PullRequests.List(ctx, owner, repo, opts)
 		if err != nil {
 			return nil, fmt.Errorf("error listing pull requests for %s/%s: %w", owner, repo, err)
 		}
 
 		for _, pullRequest := range pullRequests {
 			if *pullRequest.Number < minimumNumber {
-				break
+				return openPRs, nil // Sorted newest first, so nothing further is above the minimum
 			}
-			if *pullRequest.Draft {
-				continue
-			}
-
-			user := *pullRequest.User.Login
-			// If filtering community PRs, skip PRs by internal team members and users in SkipUsersMap
-			slogs.Logr.Info("Checking user against skip list", "user", user, "skip list", cfg.SkipUsersMap)
-			if filterCommunity && (teamMembers[user] || cfg.SkipUsersMap[user]) {
-				slogs.Logr.Info("Pull request does not meet criteria, skipping", "PR", pullRequest.GetHTMLURL(), "user", user)
-				continue
-			}
-
-			slogs.Logr.Info("Pull request meets criteria, adding to final list", "PR", pullRequest.GetHTMLURL(), "user", user)
-			finalPRs = append(finalPRs, pullRequest)
+			openPRs = append(openPRs, pullRequest)
 		}
 
 		if resp.NextPage == 0 {
@@ -65,5 +42,25 @@ func findPRs(cfg *config.Config, teamMembers map[string]bool, githubClient *gith
 		opts.Page = resp.NextPage // Set next page number
 	}
 
-	return finalPRs, nil
+	return openPRs, nil
+}
+
+// MatchesPR reports whether a pull request should be checked; with filterCommunity set, PRs by the internal team and skip users are excluded
+func MatchesPR(cfg *config.Config, teamMembers map[string]bool, pullRequest *github.PullRequest, minimumNumber int, filterCommunity bool) bool {
+	if pullRequest.GetNumber() < minimumNumber {
+		return false
+	}
+	if pullRequest.GetDraft() || pullRequest.GetState() == "closed" {
+		return false
+	}
+
+	user := pullRequest.GetUser().GetLogin()
+	// If filtering community PRs, skip PRs by internal team members and users in SkipUsersMap
+	slogs.Logr.Info("Checking user against skip list", "user", user, "skip list", cfg.SkipUsersMap)
+	if filterCommunity && (teamMembers[user] || cfg.SkipUsersMap[user]) {
+		slogs.Logr.Info("Pull request does not meet criteria, skipping", "PR", pullRequest.GetHTMLURL(), "user", user)
+		return false
+	}
+
+	return true
 }
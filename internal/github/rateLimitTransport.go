@@ -0,0 +1,283 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+)
+
+const (
+	maxRetries  = 4
+	baseBackoff = 2 * time.Second
+	// minSecondaryWait is how long to back off from a secondary rate limit that came without a Retry-After, as GitHub recommends
+	minSecondaryWait = time.Minute
+	// maxSecondaryWait bounds how long a single Retry-After is honored, in case GitHub asks for something unreasonable
+	maxSecondaryWait = 5 * time.Minute
+	// unmeteredResource is reported by resourceFromPath for GET /rate_limit, which doesn't count against any budget
+	// and so is never held back waiting for a reset. The readiness probe relies on this to stay fast when the budget is spent.
+	unmeteredResource = "rate_limit"
+)
+
+// rateLimitTransport keeps the bot within GitHub's rate limits.
+// It waits for the primary limit to reset once the budget is spent, backs off from secondary limits,
+// retries idempotent requests that fail with server or network errors, and counts every call for LogAPIUsage.
+//
+// Waiting happens before a response with no remaining budget is returned, so go-github never sees an exhausted
+// limit and doesn't short-circuit later calls with a *github.RateLimitError.
+//
+// The app's JWT and each installation token have their own budget, and so does each rate limit resource such as
+// core or search, so budgets are tracked per credential and resource.
+type rateLimitTransport struct {
+	base http.RoundTripper
+
+	mu      sync.Mutex
+	budgets map[string]rateBudget
+}
+
+// rateBudget is the remaining budget GitHub last reported for one credential and resource
+type rateBudget struct {
+	remaining int
+	reset     time.Time
+}
+
+func newRateLimitTransport(base http.RoundTripper) *rateLimitTransport {
+	return &rateLimitTransport{
+		base:    base,
+		budgets: map[string]rateBudget{},
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	idempotent := req.Method == http.MethodGet || req.Method == http.MethodHead
+	// Requests can only be replayed if their body can be recreated
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	resource := resourceFromPath(req.URL.Path)
+	metered := resource != unmeteredResource
+	key := budgetKey(req, resource)
+
+	for attempt := 0; ; attempt++ {
+		if metered {
+			if err := t.waitForPrimaryReset(ctx, key); err != nil {
+				return nil, err
+			}
+		}
+
+		attemptReq := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(ctx)
+			attemptReq.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		apiUsage.record(ctx, req)
+		if err != nil {
+			if idempotent && attempt < maxRetries && ctx.Err() == nil {
+				slogs.Logr.Warn("GitHub request failed, retrying", "method", req.Method, "path", req.URL.Path, "attempt", attempt+1, "error", err)
+				if err := sleepContext(ctx, backoff(attempt)); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			return nil, err
+		}
+
+		if resource := resp.Header.Get("X-RateLimit-Resource"); resource != "" {
+			key = budgetKey(req, resource)
+		}
+		t.updateRate(key, resp)
+
+		if wait, limited := secondaryLimitWait(resp, attempt); limited && replayable && attempt < maxRetries {
+			slogs.Logr.Warn("Hit GitHub secondary rate limit, waiting before retrying", "method", req.Method, "path", req.URL.Path, "wait", wait.String())
+			drain(resp)
+			if err := sleepContext(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if isPrimaryLimited(resp) && metered && replayable && attempt < maxRetries {
+			drain(resp)
+			continue // waitForPrimaryReset sleeps until the reset at the top of the loop
+		}
+
+		if resp.StatusCode >= 500 && idempotent && attempt < maxRetries {
+			slogs.Logr.Warn("GitHub returned a server error, retrying", "method", req.Method, "path", req.URL.Path, "status", resp.StatusCode, "attempt", attempt+1)
+			drain(resp)
+			if err := sleepContext(ctx, backoff(attempt)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if resp.StatusCode < 400 && metered {
+			if err := t.waitForPrimaryReset(ctx, key); err != nil {
+				drain(resp)
+				return nil, err
+			}
+		}
+		return resp, nil
+	}
+}
+
+// budgetKey identifies the budget a request counts against, without keeping the credential itself
+func budgetKey(req *http.Request, resource string) string {
+	credential := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return hex.EncodeToString(credential[:8]) + "/" + resource
+}
+
+// resourceFromPath guesses the rate limit resource a request is counted against before GitHub reports it
+func resourceFromPath(path string) string {
+	parts := apiPath(path)
+	switch {
+	case len(parts) >= 1 && parts[0] == "graphql", len(parts) >= 2 && parts[0] == "api" && parts[1] == "graphql":
+		return "graphql"
+	case len(parts) >= 2 && parts[0] == "search" && parts[1] == "code":
+		return "code_search"
+	case len(parts) >= 1 && parts[0] == "search":
+		return "search"
+	case len(parts) == 1 && parts[0] == "rate_limit":
+		return unmeteredResource
+	}
+	return "core"
+}
+
+// updateRate remembers the budget reported by the X-RateLimit headers under key
+func (t *rateLimitTransport) updateRate(key string, resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	resetUnix, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+
+	reset := time.Unix(resetUnix, 0)
+
+	t.mu.Lock()
+	// Installation tokens are replaced hourly, so forget budgets that have already reset
+	now := time.Now()
+	for k, budget := range t.budgets {
+		if budget.reset.Before(now) {
+			delete(t.budgets, k)
+		}
+	}
+	t.budgets[key] = rateBudget{remaining: remaining, reset: reset}
+	t.mu.Unlock()
+
+	// Metrics and LogAPIUsage report the core budget, which is the one the jobs spend
+	if resource := resp.Header.Get("X-RateLimit-Resource"); resource == "" || resource == "core" {
+		apiUsage.setBudget(limit, remaining, reset)
+	}
+}
+
+// waitForPrimaryReset blocks while the primary budget for key is exhausted
+func (t *rateLimitTransport) waitForPrimaryReset(ctx context.Context, key string) error {
+	t.mu.Lock()
+	budget, ok := t.budgets[key]
+	t.mu.Unlock()
+	if !ok || budget.remaining != 0 || !time.Now().Before(budget.reset) {
+		return nil
+	}
+	reset := budget.reset
+
+	// A second of slack so the reset has definitely happened on GitHub's side
+	wait := time.Until(reset) + time.Second
+	slogs.Logr.Warn("GitHub API rate limit exhausted, waiting for reset", "reset", reset.Format(time.RFC3339), "wait", wait.String())
+	return sleepContext(ctx, wait)
+}
+
+// isPrimaryLimited reports a 403 or 429 caused by an exhausted primary budget
+func isPrimaryLimited(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return false
+	}
+	return resp.Header.Get("X-RateLimit-Remaining") == "0"
+}
+
+// secondaryLimitWait reports how long to back off after a secondary rate limit.
+// Retry-After is honored when GitHub sends it, otherwise the wait starts at a minute and doubles with each attempt.
+func secondaryLimitWait(resp *http.Response, attempt int) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return min(time.Duration(seconds)*time.Second, maxSecondaryWait), true
+	}
+	if isPrimaryLimited(resp) {
+		return 0, false
+	}
+	// A 403 is also returned for missing permissions, which only the message tells apart
+	if resp.StatusCode == http.StatusForbidden && !mentionsSecondaryLimit(resp) {
+		return 0, false
+	}
+	return min(minSecondaryWait<<attempt, maxSecondaryWait), true
+}
+
+// mentionsSecondaryLimit reports whether the error message in resp is about a secondary rate limit.
+// The body is replaced so it can still be read by the caller.
+func mentionsSecondaryLimit(resp *http.Response) bool {
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
+
+// backoff is exponential with full jitter
+func backoff(attempt int) time.Duration {
+	ceiling := baseBackoff << attempt
+	return time.Duration(rand.Int63n(int64(ceiling))) + time.Second
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// drain discards a response that is about to be retried so the connection can be reused
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
+
+// apiPath splits a REST API request path into its segments
+func apiPath(path string) []string {
+	// GitHub Enterprise serves the same API under /api/v3
+	path = strings.TrimPrefix(strings.Trim(path, "/"), "api/v3/")
+	return strings.Split(path, "/")
+}
+
+// repoFromPath returns owner/repo for /repos/{owner}/{repo}/... requests
+func repoFromPath(path string) string {
+	parts := apiPath(path)
+	if len(parts) >= 3 && parts[0] == "repos" {
+		return parts[1] + "/" + parts[2]
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/google/go-github/v60/github"
+
+	"github.com/chia-network/github-bot/internal/config"
+)
+
+// FlagSensitiveFiles returns the files a PR changes that match the repo's sensitive_paths, and adds the label_sensitive
+// warning label while there are any, removing it once there are none
+func FlagSensitiveFiles(ctx context.Context, client *github.Client, cfg *config.Config, owner, repo string, pr *github.PullRequest) ([]string, error) {
+	policy := cfg.PolicyFor(owner + "/" + repo)
+	files, err := ChangedFiles(ctx, client, owner, repo, pr.GetNumber())
+	if err != nil {
+		return nil, err
+	}
+	sensitive := MatchingPaths(files, policy.SensitivePaths)
+	if len(sensitive) > 0 {
+		slogs.Logr.Warn("PR waiting for CI approval modifies sensitive paths", "PR", pr.GetNumber(), "repository", owner+"/"+repo, "files", sensitive)
+	}
+	if policy.LabelSensitive == "" {
+		return sensitive, nil
+	}
+
+	labeled := false
+	for _, label := range pr.Labels {
+		if label.GetName() == policy.LabelSensitive {
+			labeled = true
+		}
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	if len(sensitive) > 0 && !labeled {
+		_, _, err = client.Issues.AddLabelsToIssue(reqCtx, owner, repo, pr.GetNumber(), []string{policy.LabelSensitive})
+		if err != nil {
+			return sensitive, fmt.Errorf("error adding label %s to PR #%d: %w", policy.LabelSensitive, pr.GetNumber(), err)
+		}
+	}
+	if len(sensitive) == 0 && labeled {
+		_, err = client.Issues.RemoveLabelForIssue(reqCtx, owner, repo, pr.GetNumber(), policy.LabelSensitive)
+		if err != nil {
+			return sensitive, fmt.Errorf("error removing label %s from PR #%d: %w", policy.LabelSensitive, pr.GetNumber(), err)
+		}
+	}
+	return sensitive, nil
+}
@@ -0,0 +1,102 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/google/go-github/v60/github"
+
+	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/templates"
+)
+
+// signoffCheckName is the check run or commit status the sign-off result is published as
+const signoffCheckName = "github-bot/dco"
+
+// signoffRemediation is shown on a failing sign-off check
+const signoffRemediation = `### How to fix
+
+Add a ` + "`Signed-off-by: Your Name <you@example.com>`" + ` line with the commit author's email to the end of each commit message,
+for example with ` + "`git rebase --signoff <base branch>`" + `, and force push the branch. By signing off you certify the
+Developer Certificate of Origin: https://developercertificate.org/`
+
+// The reasons a commit fails the sign-off check, shown in signoff_comment
+const (
+	signoffMissing  = "missing_signoff"
+	signoffMismatch = "signoff_mismatch"
+)
+
+// signoffPattern matches a Developer Certificate of Origin trailer, capturing the name and email
+var signoffPattern = regexp.MustCompile(`(?im)^\s*Signed-off-by:\s*(.*?)\s*<([^>]+)>\s*$`)
+
+// checkSignoff publishes whether every commit in the PR has a Signed-off-by trailer matching its author as a check,
+// and posts the repo's signoff_comment while any commit fails, updating it as commits are fixed and removing it once they all pass
+func checkSignoff(ctx context.Context, githubClient *github.Client, cfg *config.Config, pr *github.PullRequest, commits []*github.RepositoryCommit) error {
+	owner, repo, number := pr.GetBase().GetRepo().GetOwner().GetLogin(), pr.GetBase().GetRepo().GetName(), pr.GetNumber()
+	failures := signoffFailures(commits)
+	result := checkResult{Name: signoffCheckName, Passed: len(failures) == 0}
+
+	if result.Passed {
+		slogs.Logr.Info("Every commit is signed off", "PR", number, "repository", owner+"/"+repo)
+		result.Title = "All commits are signed off by their author"
+		result.Summary = "Every commit has a `Signed-off-by:` line with its author's email."
+		err := publishCheck(ctx, githubClient, cfg, owner, repo, pr.GetHead().GetSHA(), result)
+		if err != nil {
+			return err
+		}
+		return DeleteComment(ctx, githubClient, owner, repo, number, SignoffCommentKey)
+	}
+
+	slogs.Logr.Info("PR has commits without a valid sign-off", "PR", number, "repository", owner+"/"+repo, "commits", len(failures))
+	result.Title = fmt.Sprintf("%d %s missing a sign-off by %s author", len(failures), plural(len(failures), "commit is", "commits are"), plural(len(failures), "its", "their"))
+	result.Summary = "This repository requires every commit to be signed off under the Developer Certificate of Origin.\n\n" + commitTable(failures) + "\n" + signoffRemediation
+	err := publishCheck(ctx, githubClient, cfg, owner, repo, pr.GetHead().GetSHA(), result)
+	if err != nil {
+		return err
+	}
+
+	data := NewTemplateData(cfg, pr)
+	data.Commits = failures
+	body, err := templates.Render(templates.SignoffComment, cfg.PolicyFor(owner+"/"+repo).Templates.SignoffComment, data)
+	if err != nil {
+		return err
+	}
+	return UpsertComment(ctx, githubClient, owner, repo, number, SignoffCommentKey, body)
+}
+
+// signoffFailures returns the commits without a Signed-off-by trailer for their author's email.
+// Merge commits are skipped, since they are made by GitHub or git rather than written by the author.
+func signoffFailures(commits []*github.RepositoryCommit) []templates.Commit {
+	var failures []templates.Commit
+	for _, commit := range commits {
+		if commit == nil || commit.Commit == nil || len(commit.Parents) > 1 {
+			continue
+		}
+		author := commit.Commit.GetAuthor()
+		failed := templates.Commit{SHA: commit.GetSHA(), Author: author.GetName(), Email: author.GetEmail()}
+
+		signoffs := signoffPattern.FindAllStringSubmatch(commit.Commit.GetMessage(), -1)
+		if len(signoffs) == 0 {
+			failed.Reason = signoffMissing
+			failed.Description = "no `Signed-off-by:` line"
+			failures = append(failures, failed)
+			continue
+		}
+		matched := false
+		for _, signoff := range signoffs {
+			if strings.EqualFold(strings.TrimSpace(signoff[2]), author.GetEmail()) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			failed.Reason = signoffMismatch
+			failed.Description = "no `Signed-off-by:` line has the author's email"
+			failures = append(failures, failed)
+		}
+	}
+	return failures
+}
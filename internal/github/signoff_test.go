@@ -0,0 +1,84 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v60/github"
+
+	"github.com/chia-network/github-bot/internal/templates"
+)
+
+func TestSignoffFailures(t *testing.T) {
+	commit := func(message, email string, parents int) *github.RepositoryCommit {
+		return &github.RepositoryCommit{
+			SHA: github.String("abc123"),
+			Commit: &github.Commit{
+				Message: github.String(message),
+				Author:  &github.CommitAuthor{Name: github.String("Alice"), Email: github.String(email)},
+			},
+			Parents: make([]*github.Commit, parents),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		commit *github.RepositoryCommit
+		// reason is empty when the commit should pass
+		reason string
+	}{
+		{"matching trailer", commit("Fix bug\n\nSigned-off-by: Alice <alice@example.com>", "alice@example.com", 1), ""},
+		{"no trailer", commit("Fix bug", "alice@example.com", 1), signoffMissing},
+		{"trailer for someone else", commit("Fix bug\n\nSigned-off-by: Bob <bob@example.com>", "alice@example.com", 1), signoffMismatch},
+		{"author among several trailers", commit("Fix bug\n\nSigned-off-by: Bob <bob@example.com>\nSigned-off-by: Alice <alice@example.com>\nCo-authored-by: Carol <carol@example.com>", "alice@example.com", 1), ""},
+		{"several trailers without the author", commit("Fix bug\n\nSigned-off-by: Bob <bob@example.com>\nSigned-off-by: Carol <carol@example.com>", "alice@example.com", 1), signoffMismatch},
+		{"lowercase key", commit("Fix bug\n\nsigned-off-by: Alice <alice@example.com>", "alice@example.com", 1), ""},
+		{"email in a different case", commit("Fix bug\n\nSigned-off-by: Alice <Alice@Example.com>", "alice@example.com", 1), ""},
+		{"trailer in the middle of the body", commit("Fix bug\n\nSigned-off-by: Alice <alice@example.com>\n\nMore details about the fix", "alice@example.com", 1), ""},
+		{"indented trailer with extra spaces", commit("Fix bug\n\n  Signed-off-by:   Alice   <alice@example.com>  ", "alice@example.com", 1), ""},
+		{"trailer mentioned inside a line", commit("Fix bug, see Signed-off-by: Alice <alice@example.com> above", "alice@example.com", 1), signoffMissing},
+		{"trailer without an email", commit("Fix bug\n\nSigned-off-by: Alice", "alice@example.com", 1), signoffMissing},
+		{"merge commit", commit("Merge branch 'main' into feature", "alice@example.com", 2), ""},
+		{"root commit", commit("Initial commit", "alice@example.com", 0), signoffMissing},
+	}
+	for _, test := range tests {
+		failures := signoffFailures([]*github.RepositoryCommit{test.commit})
+		if test.reason == "" {
+			if len(failures) != 0 {
+				t.Errorf("%s: signoffFailures() = %+v, want none", test.name, failures)
+			}
+			continue
+		}
+		want := templates.Commit{SHA: "abc123", Author: "Alice", Email: "alice@example.com", Reason: test.reason}
+		if len(failures) != 1 {
+			t.Errorf("%s: signoffFailures() = %+v, want one failure", test.name, failures)
+			continue
+		}
+		got := failures[0]
+		got.Description = ""
+		if got != want {
+			t.Errorf("%s: signoffFailures() = %+v, want %+v", test.name, got, want)
+		}
+	}
+}
+
+func TestSignoffPattern(t *testing.T) {
+	tests := []struct {
+		line  string
+		name  string
+		email string
+	}{
+		{"Signed-off-by: Alice Smith <alice@example.com>", "Alice Smith", "alice@example.com"},
+		{"SIGNED-OFF-BY: Bob <bob@example.com>", "Bob", "bob@example.com"},
+		{"Signed-off-by:<carol@example.com>", "", "carol@example.com"},
+	}
+	for _, test := range tests {
+		match := signoffPattern.FindStringSubmatch(test.line)
+		if match == nil {
+			t.Errorf("signoffPattern did not match %q", test.line)
+			continue
+		}
+		if match[1] != test.name || match[2] != test.email {
+			t.Errorf("signoffPattern on %q = (%q, %q), want (%q, %q)", test.line, match[1], match[2], test.name, test.email)
+		}
+	}
+}
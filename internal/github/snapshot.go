@@ -0,0 +1,113 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+
+	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/metrics"
+)
+
+// Snapshot caches the internal team and the open pull requests of each repo so several jobs in a cycle share one fetch
+type Snapshot struct {
+	githubClient *github.Client
+	cfg          *config.Config
+	ttl          time.Duration
+
+	mu           sync.Mutex
+	teamMembers  map[string]bool
+	teamFetched  time.Time
+	pullRequests map[string][]*github.PullRequest
+	prsFetched   map[string]time.Time
+}
+
+// NewSnapshot creates an empty snapshot. Cached data is refetched once it is older than ttl; a ttl of 0 keeps it for the life of the snapshot.
+func NewSnapshot(githubClient *github.Client, cfg *config.Config, ttl time.Duration) *Snapshot {
+	return &Snapshot{
+		githubClient: githubClient,
+		cfg:          cfg,
+		ttl:          ttl,
+		pullRequests: map[string][]*github.PullRequest{},
+		prsFetched:   map[string]time.Time{},
+	}
+}
+
+func (s *Snapshot) expired(fetched time.Time) bool {
+	return s.ttl > 0 && time.Since(fetched) > s.ttl
+}
+
+// TeamMembers returns the internal team, fetching it if it is not cached yet
+func (s *Snapshot) TeamMembers(ctx context.Context) (map[string]bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.teamMembers == nil || s.expired(s.teamFetched) {
+		teamMembers, err := GetTeamMemberList(ctx, s.githubClient, s.cfg.InternalTeam, s.cfg.InternalTeamIgnoredUsers)
+		if err != nil {
+			return nil, err
+		}
+		s.teamMembers = teamMembers
+		s.teamFetched = time.Now()
+	}
+	return s.teamMembers, nil
+}
+
+// CommunityPRs returns the open PRs in the repo by authors outside the internal team and skip list
+func (s *Snapshot) CommunityPRs(ctx context.Context, owner, repo string, minimumNumber int) ([]*github.PullRequest, error) {
+	return s.filteredPRs(ctx, owner, repo, minimumNumber, true)
+}
+
+// AllPRs returns every open, non-draft PR in the repo
+func (s *Snapshot) AllPRs(ctx context.Context, owner, repo string, minimumNumber int) ([]*github.PullRequest, error) {
+	return s.filteredPRs(ctx, owner, repo, minimumNumber, false)
+}
+
+func (s *Snapshot) filteredPRs(ctx context.Context, owner, repo string, minimumNumber int, filterCommunity bool) ([]*github.PullRequest, error) {
+	teamMembers, err := s.TeamMembers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := fmt.Sprintf("%s/%s#%d", owner, repo, minimumNumber)
+	pullRequests, ok := s.pullRequests[key]
+	if !ok || s.expired(s.prsFetched[key]) {
+		pullRequests, err = listOpenPRs(ctx, s.githubClient, owner, repo, minimumNumber)
+		if err != nil {
+			return nil, err
+		}
+		s.pullRequests[key] = pullRequests
+		s.prsFetched[key] = time.Now()
+	}
+
+	var finalPRs []*github.PullRequest
+	for _, pullRequest := range pullRequests {
+		if MatchesPR(s.cfg, teamMembers, pullRequest, minimumNumber, filterCommunity) {
+			finalPRs = append(finalPRs, pullRequest)
+		}
+	}
+	if filterCommunity {
+		metrics.CommunityPRs.WithLabelValues(owner + "/" + repo).Set(float64(len(finalPRs)))
+	}
+	return finalPRs, nil
+}
+
+// InvalidateTeam drops the cached team, e.g. after a membership change
+func (s *Snapshot) InvalidateTeam() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.teamMembers = nil
+}
+
+// Invalidate drops everything cached so the next call refetches from GitHub
+func (s *Snapshot) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.teamMembers = nil
+	s.pullRequests = map[string][]*github.PullRequest{}
+	s.prsFetched = map[string]time.Time{}
+}
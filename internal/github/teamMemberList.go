@@ -9,7 +9,7 @@ import (
 )
 
 // GetTeamMemberList obtains a list of teammembers
-func GetTeamMemberList(githubClient *github.Client, internalTeam string, internalTeamIgnoredMembers []string) (map[string]bool, error) {
+func GetTeamMemberList(ctx context.Context, githubClient *github.Client, internalTeam string, internalTeamIgnoredMembers []string) (map[string]bool, error) {
 	teamMembers := make(map[string]bool)
 
 	teamParts := strings.Split(internalTeam, "/")
@@ -27,7 +27,7 @@ func GetTeamMemberList(githubClient *github.Client, internalTeam string, interna
 
 	for {
 		teamOpts.ListOptions.Page++
-		members, resp, err := githubClient.Teams.ListTeamMembersBySlug(context.TODO(), teamParts[0], teamParts[1], teamOpts)
+		members, resp, err := githubClient.Teams.ListTeamMembersBySlug(ctx, teamParts[0], teamParts[1], teamOpts)
 		if err != nil {
 			return nil, fmt.Errorf("error getting team %s: %w", internalTeam, err)
 		}
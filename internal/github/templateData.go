@@ -0,0 +1,121 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+
+	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/templates"
+)
+
+// TemplateData returns the data the stale notification templates are rendered with
+func (p StalePR) TemplateData(cfg *config.Config) templates.Data {
+	return templateData(cfg, p.Owner, p.Repo, p.PRNumber, p.URL, p.Title, p.Author, p.CreatedAt, p.LastTeamActivity)
+}
+
+// TemplateData returns the data the stale issue notification templates are rendered with
+func (i StaleIssue) TemplateData(cfg *config.Config) templates.Data {
+	data := templateData(cfg, i.Owner, i.Repo, i.IssueNumber, i.URL, i.Title, i.Author, i.CreatedAt, i.LastTeamActivity)
+	data.StaleAfter = cfg.PolicyFor(i.Owner + "/" + i.Repo).StaleIssuesAfter()
+	return data
+}
+
+// TemplateData returns the data the pending CI notification templates are rendered with
+func (p PendingPR) TemplateData(cfg *config.Config) templates.Data {
+	data := templateData(cfg, p.Owner, p.Repo, p.PRNumber, p.URL, p.Title, p.Author, p.CreatedAt, p.LastTeamActivity)
+	data.SensitiveFiles = p.SensitiveFiles
+	return data
+}
+
+// TemplateData returns the data the unsigned commit comment is rendered with
+func (p UnsignedPRs) TemplateData(cfg *config.Config) templates.Data {
+	data := templateData(cfg, p.Owner, p.Repo, p.PRNumber, p.URL, p.Title, p.Author, p.CreatedAt, time.Time{})
+	data.Commits = p.Commits
+	return data
+}
+
+// NewTemplateData returns the data any template can use that comes from the pull request itself
+func NewTemplateData(cfg *config.Config, pr *github.PullRequest) templates.Data {
+	repo := pr.GetBase().GetRepo()
+	return templateData(cfg, repo.GetOwner().GetLogin(), repo.GetName(), pr.GetNumber(), pr.GetHTMLURL(), pr.GetTitle(), pr.GetUser().GetLogin(), pr.GetCreatedAt().Time, time.Time{})
+}
+
+func templateData(cfg *config.Config, owner, repo string, number int, url, title, author string, createdAt, lastTeamActivity time.Time) templates.Data {
+	fullName := owner + "/" + repo
+	policy := cfg.PolicyFor(fullName)
+	data := templates.Data{
+		Repo:             fullName,
+		Owner:            owner,
+		RepoName:         repo,
+		Number:           number,
+		Title:            title,
+		Author:           author,
+		URL:              url,
+		Team:             cfg.InternalTeam,
+		CreatedAt:        createdAt,
+		Age:              templates.WholeDays(time.Since(createdAt)),
+		LastTeamActivity: lastTeamActivity,
+		StaleAfter:       policy.StaleAfter,
+		PendingCIGrace:   policy.PendingCIGrace,
+	}
+	if !lastTeamActivity.IsZero() {
+		data.SinceTeamActivity = templates.WholeDays(time.Since(lastTeamActivity))
+	}
+	return data
+}
+
+// IssueTemplateData looks up what the stale issue templates can use for a real issue, for previewing them
+func IssueTemplateData(ctx context.Context, githubClient *github.Client, cfg *config.Config, snapshot *Snapshot, owner, repo string, number int) (templates.Data, error) {
+	issue, _, err := githubClient.Issues.Get(ctx, owner, repo, number)
+	if err != nil {
+		return templates.Data{}, fmt.Errorf("error fetching issue: %w", err)
+	}
+	teamMembers, err := snapshot.TeamMembers(ctx)
+	if err != nil {
+		return templates.Data{}, fmt.Errorf("error getting team members: %w", err)
+	}
+	_, lastTeamActivity, err := isStale(ctx, githubClient, owner, repo, number, issue.GetCreatedAt().Time, teamMembers, time.Now())
+	if err != nil {
+		return templates.Data{}, fmt.Errorf("error finding last team activity: %w", err)
+	}
+	return newStaleIssue(owner, repo, issue, lastTeamActivity).TemplateData(cfg), nil
+}
+
+// PRTemplateData looks up everything the templates can use for a real pull request, for previewing the named template
+func PRTemplateData(ctx context.Context, githubClient *github.Client, cfg *config.Config, snapshot *Snapshot, name, owner, repo string, number int) (templates.Data, error) {
+	pr, _, err := githubClient.PullRequests.Get(ctx, owner, repo, number)
+	if err != nil {
+		return templates.Data{}, fmt.Errorf("error fetching pull request: %w", err)
+	}
+	teamMembers, err := snapshot.TeamMembers(ctx)
+	if err != nil {
+		return templates.Data{}, fmt.Errorf("error getting team members: %w", err)
+	}
+
+	// With a cutoff of now, no activity counts as recent, so this only finds the last team activity
+	_, lastTeamActivity, err := isStale(ctx, githubClient, owner, repo, number, pr.GetCreatedAt().Time, teamMembers, time.Now())
+	if err != nil {
+		return templates.Data{}, fmt.Errorf("error finding last team activity: %w", err)
+	}
+	commits, err := listCommits(ctx, githubClient, pr)
+	if err != nil {
+		return templates.Data{}, fmt.Errorf("error listing commits: %w", err)
+	}
+	files, err := ListFiles(ctx, githubClient, owner, repo, number)
+	if err != nil {
+		return templates.Data{}, err
+	}
+
+	data := templateData(cfg, owner, repo, pr.GetNumber(), pr.GetHTMLURL(), pr.GetTitle(), pr.GetUser().GetLogin(), pr.GetCreatedAt().Time, lastTeamActivity)
+	data.Commits = unsignedCommits(commits)
+	if name == templates.SignoffComment {
+		data.Commits = signoffFailures(commits)
+	}
+	policy := cfg.PolicyFor(owner + "/" + repo)
+	data.SensitiveFiles = MatchingPaths(FileNames(files), policy.SensitivePaths)
+	data.ChangedLines = ChangedLines(files, policy.SizeLabels.Exclude)
+	return data, nil
+}
@@ -0,0 +1,57 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+
+	"github.com/chia-network/github-bot/internal/config"
+)
+
+// ValidateConfig checks that the team, repos and labels named in the config exist on GitHub, returning every problem joined together
+func ValidateConfig(ctx context.Context, githubClient *github.Client, cfg *config.Config) error {
+	var errs []error
+
+	if org, team, ok := strings.Cut(cfg.InternalTeam, "/"); ok {
+		_, resp, err := githubClient.Teams.GetTeamBySlug(ctx, org, team)
+		if err != nil {
+			errs = append(errs, describeLookupError(resp, err, fmt.Sprintf("internal_team %s", cfg.InternalTeam)))
+		}
+	}
+
+	for _, fullRepo := range cfg.CheckRepos {
+		owner, repo, ok := strings.Cut(fullRepo.Name, "/")
+		if !ok {
+			continue // Reported by config.Validate
+		}
+		_, resp, err := githubClient.Repositories.Get(ctx, owner, repo)
+		if err != nil {
+			errs = append(errs, describeLookupError(resp, err, fmt.Sprintf("repository %s", fullRepo.Name)))
+			continue
+		}
+
+		policy := cfg.PolicyFor(fullRepo.Name)
+		for _, label := range []string{policy.LabelInternal, policy.LabelExternal, policy.LabelSensitive} {
+			if label == "" {
+				continue
+			}
+			_, resp, err := githubClient.Issues.GetLabel(ctx, owner, repo, label)
+			if err != nil {
+				errs = append(errs, describeLookupError(resp, err, fmt.Sprintf("label %q in %s", label, fullRepo.Name)))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func describeLookupError(resp *github.Response, err error, what string) error {
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s does not exist or is not visible to the bot", what)
+	}
+	return fmt.Errorf("error looking up %s: %w", what, err)
+}
@@ -0,0 +1,91 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/google/go-github/v60/github"
+
+	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/database"
+	"github.com/chia-network/github-bot/internal/templates"
+)
+
+// WelcomeFirstTimeContributors welcomes the authors of community PRs who have never had a PR merged in the repo
+func WelcomeFirstTimeContributors(ctx context.Context, githubClient *github.Client, cfg *config.Config, snapshot *Snapshot, store database.WelcomeStore) error {
+	for _, fullRepo := range cfg.CheckRepos {
+		parts := strings.Split(fullRepo.Name, "/")
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid repository name - must contain owner and repository: %s", fullRepo.Name)
+		}
+		owner, repo := parts[0], parts[1]
+
+		communityPRs, err := snapshot.CommunityPRs(ctx, owner, repo, fullRepo.MinimumNumber)
+		if err != nil {
+			return err
+		}
+		for _, pr := range communityPRs {
+			err = WelcomePR(ctx, githubClient, cfg, store, pr)
+			if err != nil {
+				slogs.Logr.Error("Error welcoming first-time contributor", "PR", pr.GetNumber(), "repository", fullRepo.Name, "error", err)
+			}
+		}
+	}
+	return nil
+}
+
+// WelcomePR posts the welcome_comment and adds the welcome label when the community PR's author is new to the repo and
+// hasn't been welcomed before
+func WelcomePR(ctx context.Context, githubClient *github.Client, cfg *config.Config, store database.WelcomeStore, pr *github.PullRequest) error {
+	owner, repo, number := pr.GetBase().GetRepo().GetOwner().GetLogin(), pr.GetBase().GetRepo().GetName(), pr.GetNumber()
+	fullRepo := owner + "/" + repo
+	author := pr.GetUser().GetLogin()
+
+	welcomed, err := store.Welcomed(fullRepo, author)
+	if err != nil {
+		return err
+	}
+	if welcomed {
+		return nil
+	}
+	firstTime, err := isFirstTimeContributor(ctx, githubClient, owner, repo, pr)
+	if err != nil {
+		return err
+	}
+	if !firstTime {
+		return nil
+	}
+
+	body, err := templates.Render(templates.WelcomeComment, cfg.PolicyFor(fullRepo).Templates.WelcomeComment, NewTemplateData(cfg, pr))
+	if err != nil {
+		return err
+	}
+	slogs.Logr.Info("Welcoming first-time contributor", "PR", number, "repository", fullRepo, "user", author)
+	err = CommentOnce(ctx, githubClient, owner, repo, number, WelcomeCommentKey, body)
+	if err != nil {
+		return err
+	}
+	_, _, err = githubClient.Issues.AddLabelsToIssue(ctx, owner, repo, number, []string{cfg.Welcome.Label})
+	if err != nil {
+		return fmt.Errorf("error adding label to pull request %d: %w", number, err)
+	}
+	return store.RecordWelcome(fullRepo, author, int64(number))
+}
+
+// isFirstTimeContributor reports whether the author has never had a PR merged in the repo. GitHub's author_association
+// answers this for most authors; NONE is ambiguous, so it falls back to searching for merged PRs.
+func isFirstTimeContributor(ctx context.Context, githubClient *github.Client, owner, repo string, pr *github.PullRequest) (bool, error) {
+	switch pr.GetAuthorAssociation() {
+	case "FIRST_TIME_CONTRIBUTOR", "FIRST_TIMER":
+		return true, nil
+	case "CONTRIBUTOR", "COLLABORATOR", "MEMBER", "OWNER":
+		return false, nil
+	}
+	merged, err := mergedPRCount(ctx, githubClient, owner, repo, pr.GetUser().GetLogin())
+	if err != nil {
+		return false, err
+	}
+	return merged == 0, nil
+}
@@ -0,0 +1,193 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+
+	"github.com/chia-network/github-bot/internal/config"
+)
+
+// Check reports whether a dependency, e.g. the database or GitHub, is reachable
+type Check func(ctx context.Context) error
+
+type jobState struct {
+	interval    time.Duration
+	lastSuccess time.Time
+	lastError   string
+}
+
+var (
+	mu           sync.Mutex
+	started      = time.Now()
+	window       time.Duration
+	heartbeatURL string
+	jobs         = map[string]*jobState{}
+	checks       = map[string]Check{}
+)
+
+var heartbeatClient = &http.Client{Timeout: 10 * time.Second}
+
+// Configure applies the health section of the config
+func Configure(cfg config.HealthConfig) {
+	mu.Lock()
+	defer mu.Unlock()
+	window = cfg.Window
+	heartbeatURL = cfg.HeartbeatURL
+}
+
+// RegisterJob starts tracking a job that is expected to succeed at least once per window
+func RegisterJob(name string, interval time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := jobs[name]; !ok {
+		jobs[name] = &jobState{interval: interval}
+	}
+}
+
+// AddCheck adds a dependency check that must pass for the process to be ready
+func AddCheck(name string, check Check) {
+	mu.Lock()
+	defer mu.Unlock()
+	checks[name] = check
+}
+
+// RecordCycle records the outcome of one cycle of a job and sends the heartbeat after a success
+func RecordCycle(ctx context.Context, name string, err error) {
+	mu.Lock()
+	state, ok := jobs[name]
+	url := heartbeatURL
+	if ok {
+		if err != nil {
+			state.lastError = err.Error()
+		} else {
+			state.lastSuccess = time.Now()
+			state.lastError = ""
+		}
+	}
+	mu.Unlock()
+
+	if err == nil && url != "" {
+		sendHeartbeat(ctx, strings.ReplaceAll(url, "{job}", name), name)
+	}
+}
+
+func sendHeartbeat(ctx context.Context, url string, job string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		slogs.Logr.Error("Error creating heartbeat request", "job", job, "error", err)
+		return
+	}
+	resp, err := heartbeatClient.Do(req)
+	if err != nil {
+		slogs.Logr.Error("Error sending heartbeat", "job", job, "error", err)
+		return
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		slogs.Logr.Error("Heartbeat was rejected", "job", job, "status", resp.Status)
+	}
+}
+
+type jobStatus struct {
+	OK          bool       `json:"ok"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	Deadline    time.Time  `json:"deadline"`
+}
+
+type status struct {
+	OK     bool                 `json:"ok"`
+	Jobs   map[string]jobStatus `json:"jobs"`
+	Checks map[string]string    `json:"checks,omitempty"`
+}
+
+// jobWindow is how long a job may go without succeeding, defaulting to three of its intervals
+func jobWindow(state *jobState) time.Duration {
+	if window > 0 {
+		return window
+	}
+	return 3 * state.interval
+}
+
+func jobsStatus(now time.Time) (map[string]jobStatus, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	healthy := true
+	out := map[string]jobStatus{}
+	for name, state := range jobs {
+		// Jobs that haven't succeeded yet get a full window from startup
+		since := started
+		js := jobStatus{LastError: state.lastError}
+		if !state.lastSuccess.IsZero() {
+			since = state.lastSuccess
+			lastSuccess := state.lastSuccess
+			js.LastSuccess = &lastSuccess
+		}
+		js.Deadline = since.Add(jobWindow(state))
+		js.OK = now.Before(js.Deadline)
+		healthy = healthy && js.OK
+		out[name] = js
+	}
+	return out, healthy
+}
+
+func runChecks(ctx context.Context) (map[string]string, bool) {
+	mu.Lock()
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	pending := make(map[string]Check, len(checks))
+	for name, check := range checks {
+		pending[name] = check
+	}
+	mu.Unlock()
+	sort.Strings(names)
+
+	healthy := true
+	out := map[string]string{}
+	for _, name := range names {
+		checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err := pending[name](checkCtx)
+		cancel()
+		if err != nil {
+			healthy = false
+			out[name] = err.Error()
+			continue
+		}
+		out[name] = "ok"
+	}
+	return out, healthy
+}
+
+// Handler serves /healthz, which fails when a job has not succeeded within its window,
+// and /readyz, which additionally fails when a dependency check does
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		jobStatuses, ok := jobsStatus(time.Now())
+		writeStatus(w, status{OK: ok, Jobs: jobStatuses})
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		jobStatuses, jobsOK := jobsStatus(time.Now())
+		checkStatuses, checksOK := runChecks(r.Context())
+		writeStatus(w, status{OK: jobsOK && checksOK, Jobs: jobStatuses, Checks: checkStatuses})
+	})
+	return mux
+}
+
+func writeStatus(w http.ResponseWriter, s status) {
+	w.Header().Set("Content-Type", "application/json")
+	if !s.OK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(s)
+}
@@ -2,11 +2,11 @@ package keybase
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 
 	"github.com/chia-network/go-modules/pkg/slogs"
 )
@@ -48,11 +48,10 @@ func NewMessage(status, title, description string) WebhookMessage {
 	}
 }
 
-// SendKeybaseMsg sends a message to a specified Keybase channel
-func (msg *WebhookMessage) SendKeybaseMsg(webhookURL string) error {
-	authToken := os.Getenv("WEBHOOK_AUTH_SECRET_TOKEN")
+// SendKeybaseMsgWithToken sends a message to a specified Keybase channel using the given bearer token
+func (msg *WebhookMessage) SendKeybaseMsgWithToken(ctx context.Context, webhookURL string, authToken string) error {
 	if authToken == "" {
-		return fmt.Errorf("WEBHOOK_AUTH_SECRET_TOKEN environment variable is not set")
+		return fmt.Errorf("no auth token configured for the Keybase webhook")
 	}
 
 	payload, err := json.Marshal(msg)
@@ -61,7 +60,7 @@ func (msg *WebhookMessage) SendKeybaseMsg(webhookURL string) error {
 		return err
 	}
 
-	req, err := http.NewRequest("POST", webhookURL, bytes.NewBuffer(payload))
+	req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewBuffer(payload))
 	if err != nil {
 		slogs.Logr.Error("Error creating webhook HTTP request", "error", err)
 		return err
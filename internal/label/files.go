@@ -0,0 +1,138 @@
+package label
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/google/go-github/v60/github"
+
+	"github.com/chia-network/github-bot/internal/config"
+	github2 "github.com/chia-network/github-bot/internal/github"
+	"github.com/chia-network/github-bot/internal/templates"
+)
+
+// Files applies the labels that depend on a pull request's changed files: the repo's path_labels rules and its size label.
+// Community pull requests over the oversize threshold are also asked, once, to split the change.
+func Files(ctx context.Context, githubClient *github.Client, cfg *config.Config, teamMembers map[string]bool, pullRequest *github.PullRequest) error {
+	policy := cfg.PolicyFor(pullRequest.GetBase().GetRepo().GetFullName())
+	if !usesFiles(policy) {
+		return nil
+	}
+	owner, repo, number := pullRequest.GetBase().GetRepo().GetOwner().GetLogin(), pullRequest.GetBase().GetRepo().GetName(), pullRequest.GetNumber()
+
+	files, err := github2.ListFiles(ctx, githubClient, owner, repo, number)
+	if err != nil {
+		return err
+	}
+
+	err = pathLabels(ctx, githubClient, pullRequest, policy.PathLabels, github2.FileNames(files))
+	if err != nil {
+		return err
+	}
+
+	changedLines := github2.ChangedLines(files, policy.SizeLabels.Exclude)
+	err = sizeLabel(ctx, githubClient, pullRequest, policy.SizeLabels.Labels, changedLines)
+	if err != nil {
+		return err
+	}
+
+	user := pullRequest.GetUser().GetLogin()
+	community := !teamMembers[user] && !cfg.SkipUsersMap[user]
+	if community && policy.SizeLabels.OversizeThreshold > 0 && changedLines >= policy.SizeLabels.OversizeThreshold {
+		data := github2.NewTemplateData(cfg, pullRequest)
+		data.ChangedLines = changedLines
+		body, err := templates.Render(templates.OversizeComment, policy.Templates.OversizeComment, data)
+		if err != nil {
+			return err
+		}
+		return github2.CommentOnce(ctx, githubClient, owner, repo, number, github2.OversizeCommentKey, body)
+	}
+	return nil
+}
+
+// usesFiles reports whether the policy has anything that needs a pull request's changed files
+func usesFiles(policy config.RepoPolicy) bool {
+	return len(policy.PathLabels) > 0 || len(policy.SizeLabels.Labels) > 0 || policy.SizeLabels.OversizeThreshold > 0
+}
+
+// pathLabels adds the labels whose globs match one of the changed files and removes rule labels that no longer match
+func pathLabels(ctx context.Context, githubClient *github.Client, pullRequest *github.PullRequest, rules []config.PathLabelRule, files []string) error {
+	managed := map[string]bool{}
+	wanted := map[string]bool{}
+	for _, rule := range rules {
+		managed[rule.Label] = true
+		if len(github2.MatchingPaths(files, rule.Paths)) > 0 {
+			wanted[rule.Label] = true
+		}
+	}
+
+	var want []string
+	for _, rule := range rules {
+		if wanted[rule.Label] {
+			want = append(want, rule.Label)
+		}
+	}
+	return syncLabels(ctx, githubClient, pullRequest, managed, want)
+}
+
+// sizeLabel applies the label for the largest size the pull request reaches, replacing any other size label
+func sizeLabel(ctx context.Context, githubClient *github.Client, pullRequest *github.PullRequest, sizes []config.SizeLabel, changedLines int) error {
+	if len(sizes) == 0 {
+		return nil
+	}
+
+	managed := map[string]bool{}
+	var want string
+	best := -1
+	for _, size := range sizes {
+		managed[size.Label] = true
+		if changedLines >= size.Min && size.Min > best {
+			want, best = size.Label, size.Min
+		}
+	}
+	if want == "" {
+		return syncLabels(ctx, githubClient, pullRequest, managed, nil)
+	}
+	return syncLabels(ctx, githubClient, pullRequest, managed, []string{want})
+}
+
+// syncLabels makes the pull request carry exactly the wanted labels out of the managed ones, leaving other labels alone
+func syncLabels(ctx context.Context, githubClient *github.Client, pullRequest *github.PullRequest, managed map[string]bool, want []string) error {
+	owner, repo, number := pullRequest.GetBase().GetRepo().GetOwner().GetLogin(), pullRequest.GetBase().GetRepo().GetName(), pullRequest.GetNumber()
+
+	existing := map[string]bool{}
+	for _, label := range pullRequest.Labels {
+		existing[label.GetName()] = true
+	}
+	wanted := map[string]bool{}
+	var add []string
+	for _, label := range want {
+		wanted[label] = true
+		if !existing[label] {
+			add = append(add, label)
+			existing[label] = true // Rules can share a label
+		}
+	}
+
+	if len(add) > 0 {
+		slogs.Logr.Info("Adding labels to pull request", "PR", number, "repository", owner+"/"+repo, "labels", add)
+		_, _, err := githubClient.Issues.AddLabelsToIssue(ctx, owner, repo, number, add)
+		if err != nil {
+			return fmt.Errorf("error adding labels to pull request %d: %w", number, err)
+		}
+	}
+
+	for _, label := range pullRequest.Labels {
+		name := label.GetName()
+		if !managed[name] || wanted[name] {
+			continue
+		}
+		slogs.Logr.Info("Removing label that no longer applies", "PR", number, "repository", owner+"/"+repo, "label", name)
+		_, err := githubClient.Issues.RemoveLabelForIssue(ctx, owner, repo, number, name)
+		if err != nil {
+			return fmt.Errorf("error removing label %s from pull request %d: %w", name, number, err)
+		}
+	}
+	return nil
+}
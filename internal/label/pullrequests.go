@@ -12,9 +12,9 @@ import (
 	github2 "github.com/chia-network/github-bot/internal/github"
 )
 
-// PullRequests applies internal or community labels to pull requests
-func PullRequests(githubClient *github.Client, cfg *config.Config) error {
-	teamMembers, err := github2.GetTeamMemberList(githubClient, cfg.InternalTeam, cfg.InternalTeamIgnoredUsers)
+// PullRequests applies internal or community labels to community pull requests, and path and size labels to all pull requests
+func PullRequests(ctx context.Context, githubClient *github.Client, cfg *config.Config, snapshot *github2.Snapshot) error {
+	teamMembers, err := snapshot.TeamMembers(ctx)
 	if err != nil {
 		return fmt.Errorf("error getting team members: %w", err) // Properly handle and return error if team member list fetch fails
 	}
@@ -28,41 +28,96 @@ func PullRequests(githubClient *github.Client, cfg *config.Config) error {
 		}
 		owner, repo := parts[0], parts[1]
 
-		pullRequests, err := github2.FindCommunityPRs(cfg, teamMembers, githubClient, owner, repo, fullRepo.MinimumNumber)
+		pullRequests, err := snapshot.CommunityPRs(ctx, owner, repo, fullRepo.MinimumNumber)
 		if err != nil {
 			return fmt.Errorf("error finding community PRs: %w", err) // Handle error from finding community PRs
 		}
 
 		for _, pullRequest := range pullRequests {
-			user := *pullRequest.User.Login
+			err = PullRequest(ctx, githubClient, cfg, teamMembers, pullRequest)
+			if err != nil {
+				return err
+			}
+		}
 
-			var label string
-			if teamMembers[user] {
-				label = cfg.LabelInternal
-			} else {
-				label = cfg.LabelExternal
+		if !usesFiles(cfg.PolicyFor(fullRepo.Name)) {
+			continue
+		}
+		allPullRequests, err := snapshot.AllPRs(ctx, owner, repo, fullRepo.MinimumNumber)
+		if err != nil {
+			return fmt.Errorf("error finding PRs: %w", err)
+		}
+		for _, pullRequest := range allPullRequests {
+			err = Files(ctx, githubClient, cfg, teamMembers, pullRequest)
+			if err != nil {
+				return err
 			}
-			if label != "" {
-				slogs.Logr.Info("Labeling pull request", "PR", *pullRequest.Number, "user", user, "label", label)
-				hasLabel := false
-				for _, existingLabel := range pullRequest.Labels {
-					if *existingLabel.Name == label {
-						slogs.Logr.Info("Already labeled, skipping", "PR", *pullRequest.Number, "label", label)
-						hasLabel = true
-						break
-					}
-				}
+		}
+	}
+	return nil
+}
+
+// PullRequest applies the internal or community label to a single pull request
+func PullRequest(ctx context.Context, githubClient *github.Client, cfg *config.Config, teamMembers map[string]bool, pullRequest *github.PullRequest) error {
+	user := *pullRequest.User.Login
+	policy := cfg.PolicyFor(pullRequest.GetBase().GetRepo().GetFullName())
+
+	var label string
+	if teamMembers[user] {
+		label = policy.LabelInternal
+	} else {
+		label = policy.LabelExternal
+	}
+	if label == "" {
+		return nil
+	}
+
+	slogs.Logr.Info("Labeling pull request", "PR", *pullRequest.Number, "user", user, "label", label)
+	for _, existingLabel := range pullRequest.Labels {
+		// Either label counts, so one set by a maintainer with /bot label isn't overridden
+		if name := existingLabel.GetName(); name == policy.LabelInternal || name == policy.LabelExternal {
+			slogs.Logr.Info("Already labeled, skipping", "PR", *pullRequest.Number, "label", name)
+			return nil
+		}
+	}
+
+	allLabels := []string{label}
+	for _, labelP := range pullRequest.Labels {
+		allLabels = append(allLabels, *labelP.Name)
+	}
+	_, _, err := githubClient.Issues.AddLabelsToIssue(ctx, *pullRequest.Base.Repo.Owner.Login, *pullRequest.Base.Repo.Name, *pullRequest.Number, allLabels)
+	if err != nil {
+		return fmt.Errorf("error adding labels to pull request %d: %w", *pullRequest.Number, err) // Ensure error from label adding is handled
+	}
+	return nil
+}
 
-				if !hasLabel {
-					allLabels := []string{label}
-					for _, labelP := range pullRequest.Labels {
-						allLabels = append(allLabels, *labelP.Name)
-					}
-					_, _, err := githubClient.Issues.AddLabelsToIssue(context.TODO(), *pullRequest.Base.Repo.Owner.Login, *pullRequest.Base.Repo.Name, *pullRequest.Number, allLabels)
-					if err != nil {
-						return fmt.Errorf("error adding labels to pull request %d: %w", *pullRequest.Number, err) // Ensure error from label adding is handled
-					}
-				}
+// Set applies the internal or community label chosen by a maintainer, removing the other one if present
+func Set(ctx context.Context, githubClient *github.Client, cfg *config.Config, pullRequest *github.PullRequest, internal bool) error {
+	policy := cfg.PolicyFor(pullRequest.GetBase().GetRepo().GetFullName())
+	label, other := policy.LabelExternal, policy.LabelInternal
+	if internal {
+		label, other = policy.LabelInternal, policy.LabelExternal
+	}
+	if label == "" {
+		return fmt.Errorf("no label is configured for this repository")
+	}
+
+	owner, repo, number := pullRequest.GetBase().GetRepo().GetOwner().GetLogin(), pullRequest.GetBase().GetRepo().GetName(), pullRequest.GetNumber()
+	slogs.Logr.Info("Setting label on pull request", "PR", number, "label", label)
+	_, _, err := githubClient.Issues.AddLabelsToIssue(ctx, owner, repo, number, []string{label})
+	if err != nil {
+		return fmt.Errorf("error adding label to pull request %d: %w", number, err)
+	}
+
+	if other == "" {
+		return nil
+	}
+	for _, existingLabel := range pullRequest.Labels {
+		if existingLabel.GetName() == other {
+			_, err = githubClient.Issues.RemoveLabelForIssue(ctx, owner, repo, number, other)
+			if err != nil {
+				return fmt.Errorf("error removing label from pull request %d: %w", number, err)
 			}
 		}
 	}
@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "github_bot"
+
+var (
+	// CommunityPRs is the number of open community PRs per repository, as of the last fetch
+	CommunityPRs = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "community_prs_open",
+		Help:      "Open, non-draft pull requests from community members.",
+	}, []string{"repository"})
+
+	// StalePRs is the number of community PRs found stale by the last notify-stale cycle
+	StalePRs = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "stale_prs",
+		Help:      "Community pull requests with no team member activity within the stale window.",
+	}, []string{"repository"})
+
+	// StaleIssues is the number of community issues found stale by the last notify-stale-issues cycle
+	StaleIssues = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "stale_issues",
+		Help:      "Community issues with no team member activity within the stale window.",
+	}, []string{"repository"})
+
+	// PendingCIPRs is the number of community PRs awaiting CI approval found by the last notify-pendingci cycle
+	PendingCIPRs = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "pending_ci_prs",
+		Help:      "Community pull requests with workflow runs awaiting approval.",
+	}, []string{"repository"})
+
+	// UnsignedPRs is the number of PRs with unsigned commits found by the last notify-unsigned cycle
+	UnsignedPRs = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "unsigned_prs",
+		Help:      "Pull requests containing unsigned commits.",
+	}, []string{"repository"})
+
+	// MessagesSent counts notifications delivered, per notifier type
+	MessagesSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_sent_total",
+		Help:      "Notifications delivered.",
+	}, []string{"notifier"})
+
+	// MessagesFailed counts notifications that could not be delivered, per notifier type
+	MessagesFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_failed_total",
+		Help:      "Notifications that failed to be delivered.",
+	}, []string{"notifier"})
+
+	// AutoApprovals counts fork workflow runs approved automatically for trusted contributors, per repository
+	AutoApprovals = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "workflow_runs_auto_approved_total",
+		Help:      "Fork workflow runs approved automatically because the PR author is trusted.",
+	}, []string{"repository"})
+
+	// ReviewsRequested counts reviewers the bot requested on community PRs from CODEOWNERS, per repository
+	ReviewsRequested = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reviews_requested_total",
+		Help:      "Reviews requested from code owners on community pull requests.",
+	}, []string{"repository"})
+
+	// APICalls counts GitHub API requests, including retries, per job and repository
+	APICalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "github_api_calls_total",
+		Help:      "GitHub API requests made, including retries.",
+	}, []string{"job", "repository"})
+
+	// RateLimitRemaining is the remaining primary rate limit budget reported by the last GitHub response
+	RateLimitRemaining = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "github_rate_limit_remaining",
+		Help:      "Requests remaining in the current GitHub rate limit window.",
+	})
+
+	// RateLimitReset is when the current GitHub rate limit window resets
+	RateLimitReset = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "github_rate_limit_reset_timestamp_seconds",
+		Help:      "Unix time the current GitHub rate limit window resets.",
+	})
+
+	// JobDuration is how long each job's last cycle took
+	JobDuration = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "job_last_duration_seconds",
+		Help:      "Duration of the job's last cycle.",
+	}, []string{"job"})
+
+	// JobLastSuccess is when each job last finished a cycle without error
+	JobLastSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "job_last_success_timestamp_seconds",
+		Help:      "Unix time the job last completed a cycle successfully.",
+	}, []string{"job"})
+
+	// JobRuns counts job cycles by result
+	JobRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "job_runs_total",
+		Help:      "Job cycles run, by result.",
+	}, []string{"job", "result"})
+)
+
+// ObserveJob records the outcome of one cycle of a job
+func ObserveJob(job string, start time.Time, err error) {
+	JobDuration.WithLabelValues(job).Set(time.Since(start).Seconds())
+	if err != nil {
+		JobRuns.WithLabelValues(job, "error").Inc()
+		return
+	}
+	JobRuns.WithLabelValues(job, "success").Inc()
+	JobLastSuccess.WithLabelValues(job).SetToCurrentTime()
+}
+
+// SetPerRepo sets gauge for every repo in repos from counts, so repos with nothing to report drop back to zero
+func SetPerRepo(gauge *prometheus.GaugeVec, repos []string, counts map[string]int) {
+	for _, repo := range repos {
+		gauge.WithLabelValues(repo).Set(float64(counts[repo]))
+	}
+}
+
+// SetRateLimit records the budget reported by GitHub's X-RateLimit headers
+func SetRateLimit(remaining int, reset time.Time) {
+	RateLimitRemaining.Set(float64(remaining))
+	RateLimitReset.Set(float64(reset.Unix()))
+}
@@ -0,0 +1,29 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+)
+
+// discord posts embeds to a Discord channel webhook
+type discord struct {
+	webhookURL string
+}
+
+type discordEmbed struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type discordMessage struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+func (d *discord) Notify(ctx context.Context, msg Message) error {
+	payload := discordMessage{
+		Embeds: []discordEmbed{
+			{Title: msg.Title, Description: msg.Description},
+		},
+	}
+	return sendJSON(ctx, http.MethodPost, d.webhookURL, payload, nil)
+}
@@ -0,0 +1,35 @@
+package notify
+
+import (
+	"context"
+	"os"
+
+	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/keybase"
+)
+
+// keybaseNotifier posts to the Keybase alert-receiver
+type keybaseNotifier struct {
+	webhookURL string
+	authToken  string
+}
+
+// newKeybase falls back to the KEYBASE_WEBHOOK_URL and WEBHOOK_AUTH_SECRET_TOKEN environment variables the notify commands have always used
+func newKeybase(cfg config.NotifierConfig) *keybaseNotifier {
+	n := &keybaseNotifier{
+		webhookURL: cfg.URL,
+		authToken:  cfg.Token,
+	}
+	if n.webhookURL == "" {
+		n.webhookURL = os.Getenv("KEYBASE_WEBHOOK_URL")
+	}
+	if n.authToken == "" {
+		n.authToken = os.Getenv("WEBHOOK_AUTH_SECRET_TOKEN")
+	}
+	return n
+}
+
+func (n *keybaseNotifier) Notify(ctx context.Context, msg Message) error {
+	message := keybase.NewMessage("message", msg.Title, msg.Description)
+	return message.SendKeybaseMsgWithToken(ctx, n.webhookURL, n.authToken)
+}
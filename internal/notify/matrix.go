@@ -0,0 +1,41 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// matrix sends m.room.message events through the Matrix client-server API
+type matrix struct {
+	homeserver  string
+	roomID      string
+	accessToken string
+}
+
+type matrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+func (m *matrix) Notify(ctx context.Context, msg Message) error {
+	// The transaction ID only has to be unique per access token so retries aren't duplicated
+	txnID := fmt.Sprintf("github-bot-%d", time.Now().UnixNano())
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimSuffix(m.homeserver, "/"), url.PathEscape(m.roomID), txnID)
+
+	payload := matrixMessage{
+		MsgType:       "m.text",
+		Body:          fmt.Sprintf("%s\n%s", msg.Title, msg.Description),
+		Format:        "org.matrix.custom.html",
+		FormattedBody: fmt.Sprintf("<strong>%s</strong><br>%s", html.EscapeString(msg.Title), strings.ReplaceAll(html.EscapeString(msg.Description), "\n", "<br>")),
+	}
+	headers := map[string]string{"Authorization": fmt.Sprintf("Bearer %s", m.accessToken)}
+	return sendJSON(ctx, http.MethodPut, endpoint, payload, headers)
+}
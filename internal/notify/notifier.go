@@ -0,0 +1,119 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/metrics"
+)
+
+// Message is a single notification, e.g. one pull request that needs attention
+type Message struct {
+	Title       string
+	Description string
+}
+
+// Notifier delivers messages to a chat service
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+var client = &http.Client{Timeout: 30 * time.Second}
+
+// New returns the notifier selected by the config's type. An empty type keeps the original Keybase behavior.
+func New(cfg config.NotifierConfig) (Notifier, error) {
+	notifier, err := newNotifier(cfg)
+	if err != nil {
+		return nil, err
+	}
+	notifierType := cfg.Type
+	if notifierType == "" {
+		notifierType = "keybase"
+	}
+	return &counted{notifier: notifier, notifierType: notifierType}, nil
+}
+
+func newNotifier(cfg config.NotifierConfig) (Notifier, error) {
+	switch cfg.Type {
+	case "", "keybase":
+		n := newKeybase(cfg)
+		if n.webhookURL == "" {
+			return nil, fmt.Errorf("keybase notifier requires url or the KEYBASE_WEBHOOK_URL environment variable")
+		}
+		return n, nil
+	case "slack":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("slack notifier requires url")
+		}
+		return &slack{webhookURL: cfg.URL}, nil
+	case "discord":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("discord notifier requires url")
+		}
+		return &discord{webhookURL: cfg.URL}, nil
+	case "matrix":
+		if cfg.Homeserver == "" || cfg.RoomID == "" || cfg.Token == "" {
+			return nil, fmt.Errorf("matrix notifier requires homeserver, room_id and token")
+		}
+		return &matrix{homeserver: cfg.Homeserver, roomID: cfg.RoomID, accessToken: cfg.Token}, nil
+	case "webhook":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("webhook notifier requires url")
+		}
+		return &webhook{url: cfg.URL, headers: cfg.Headers}, nil
+	default:
+		return nil, fmt.Errorf("unknown notifier type %q", cfg.Type)
+	}
+}
+
+// counted tracks delivered and failed messages in the metrics
+type counted struct {
+	notifier     Notifier
+	notifierType string
+}
+
+func (c *counted) Notify(ctx context.Context, msg Message) error {
+	err := c.notifier.Notify(ctx, msg)
+	if err != nil {
+		metrics.MessagesFailed.WithLabelValues(c.notifierType).Inc()
+		return err
+	}
+	metrics.MessagesSent.WithLabelValues(c.notifierType).Inc()
+	return nil
+}
+
+// sendJSON sends payload as a JSON request and treats any non-2xx response as an error
+func sendJSON(ctx context.Context, method string, url string, payload interface{}, headers map[string]string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error converting message to JSON: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("error creating HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending message: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("received error response: %s", resp.Status)
+	}
+	return nil
+}
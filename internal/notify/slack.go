@@ -0,0 +1,38 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+)
+
+// slack posts Block Kit messages to a Slack incoming webhook
+type slack struct {
+	webhookURL string
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Type string     `json:"type"`
+	Text *slackText `json:"text,omitempty"`
+}
+
+type slackMessage struct {
+	// Text is the fallback shown in notifications where blocks are not rendered
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+func (s *slack) Notify(ctx context.Context, msg Message) error {
+	payload := slackMessage{
+		Text: msg.Title,
+		Blocks: []slackBlock{
+			{Type: "header", Text: &slackText{Type: "plain_text", Text: msg.Title}},
+			{Type: "section", Text: &slackText{Type: "mrkdwn", Text: msg.Description}},
+		},
+	}
+	return sendJSON(ctx, http.MethodPost, s.webhookURL, payload, nil)
+}
@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+)
+
+// webhook posts the message as plain JSON to any endpoint, with optional extra headers such as auth tokens
+type webhook struct {
+	url     string
+	headers map[string]string
+}
+
+type webhookMessage struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+func (w *webhook) Notify(ctx context.Context, msg Message) error {
+	payload := webhookMessage{
+		Title:       msg.Title,
+		Description: msg.Description,
+	}
+	return sendJSON(ctx, http.MethodPost, w.url, payload, w.headers)
+}
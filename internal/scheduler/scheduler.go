@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+)
+
+// Job is a unit of work that runs on its own interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Run starts every job immediately and then again after each interval, until the context is cancelled.
+// Jobs run independently, so a slow job never delays the others, but a single job never overlaps with itself.
+func Run(ctx context.Context, jobs []Job) {
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			runJob(ctx, job)
+		}(job)
+	}
+	wg.Wait()
+}
+
+func runJob(ctx context.Context, job Job) {
+	slogs.Logr.Info("Scheduling job", "job", job.Name, "interval", job.Interval.String())
+	for {
+		start := time.Now()
+		slogs.Logr.Info("Starting job", "job", job.Name)
+		if err := job.Run(ctx); err != nil {
+			slogs.Logr.Error("Job failed", "job", job.Name, "duration", time.Since(start).String(), "error", err)
+		} else {
+			slogs.Logr.Info("Job finished", "job", job.Name, "duration", time.Since(start).String())
+		}
+
+		slogs.Logr.Info("Waiting for next run", "job", job.Name, "duration", job.Interval.String())
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(job.Interval):
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Names of the templates that can be set in the templates section of the config
+const (
+	StaleTitle        = "stale_title"
+	StaleMessage      = "stale_message"
+	PendingCITitle    = "pending_ci_title"
+	PendingCIMessage  = "pending_ci_message"
+	UnsignedComment   = "unsigned_comment"
+	OversizeComment   = "oversize_comment"
+	WelcomeComment    = "welcome_comment"
+	SignoffComment    = "signoff_comment"
+	StaleIssueTitle   = "stale_issue_title"
+	StaleIssueMessage = "stale_issue_message"
+	// The digest titles describe a whole digest message rather than one PR or issue, and are only read from the global templates
+	StaleDigestTitle      = "stale_digest_title"
+	PendingCIDigestTitle  = "pending_ci_digest_title"
+	StaleIssueDigestTitle = "stale_issue_digest_title"
+	// AutoApprovedTitle announces a PR whose workflow runs were approved automatically
+	AutoApprovedTitle = "auto_approved_title"
+)
+
+// Default templates, used for anything not set in the config. The stale and pending CI ones reproduce the bot's original wording.
+const (
+	DefaultStaleTitle            = "The following pull request has no activity from a Chia team member in the last {{ days .StaleAfter }}"
+	DefaultStaleMessage          = "{{ .URL }}"
+	DefaultPendingCITitle        = "{{ if .SensitiveFiles }}[MODIFIES CI CONFIGURATION] {{ end }}The following pull request is waiting for approval for CI checks to run"
+	DefaultPendingCIMessage      = "{{ .URL }}{{ if .SensitiveFiles }}\nWARNING: this pull request modifies CI configuration, review these files before approving:{{ range .SensitiveFiles }}\n- {{ . }}{{ end }}{{ end }}"
+	DefaultUnsignedComment       = "Our branch protection rules require signed commits, and these commits don't have a verified signature:\n\n| Commit | Author | Reason |\n| --- | --- | --- |{{ range .Commits }}\n| {{ short .SHA }} | {{ cell .Author }} <{{ cell .Email }}> | `{{ .Reason }}`: {{ .Description }} |{{ end }}\n\nFor how to create signed commits, please visit this page: https://docs.github.com/en/authentication/managing-commit-signature-verification/signing-commits. Once your key is added to your GitHub account, you can re-sign the commits with `git rebase --exec 'git commit --amend --no-edit --no-verify -S' <base branch>` and force push the branch. This comment is updated as commits are fixed."
+	DefaultOversizeComment       = "Thanks for your contribution, @{{ .Author }}! This pull request changes {{ .ChangedLines }} lines, which makes it hard to review thoroughly. If you can, please split it into smaller pull requests that each make one change."
+	DefaultSignoffComment        = "This repository requires every commit to be signed off under the [Developer Certificate of Origin](https://developercertificate.org/), with a `Signed-off-by:` line matching the commit's author. These commits need fixing:{{ range .Commits }}\n- {{ short .SHA }} by {{ .Author }} <{{ .Email }}>: {{ if eq .Reason \"missing_signoff\" }}no sign-off{{ else }}the sign-off doesn't match the author's email{{ end }}{{ end }}\n\nYou can sign off your commits with `git rebase --signoff` and then force push the branch."
+	DefaultStaleIssueTitle       = "The following issue has no activity from a Chia team member in the last {{ days .StaleAfter }}"
+	DefaultStaleIssueMessage     = "{{ .URL }}"
+	DefaultStaleDigestTitle      = "Pull requests with no recent activity from a Chia team member"
+	DefaultPendingCIDigestTitle  = "Pull requests waiting for approval for CI checks to run"
+	DefaultStaleIssueDigestTitle = "Issues with no recent activity from a Chia team member"
+	DefaultAutoApprovedTitle     = "Workflow runs were approved automatically for a pull request from a trusted contributor"
+	DefaultWelcomeComment        = "Welcome, @{{ .Author }}, and thank you for your first pull request to {{ .Repo }}! Please take a moment to read our contributing guide: https://github.com/{{ .Repo }}/blob/HEAD/CONTRIBUTING.md\n\nOur branch protection rules require signed commits. If you haven't set up commit signing yet, this page explains how: https://docs.github.com/en/authentication/managing-commit-signature-verification/signing-commits"
+)
+
+// Data is what every template is rendered with
+type Data struct {
+	// Repo is owner/repo; Owner and RepoName are its two halves
+	Repo     string
+	Owner    string
+	RepoName string
+	Number   int
+	Title    string
+	Author   string
+	URL      string
+	// Team is the internal_team the bot treats as maintainers
+	Team string
+
+	CreatedAt time.Time
+	// Age is how long the PR has been open, in whole days
+	Age time.Duration
+	// LastTeamActivity is the last comment, review or other event by a team member, and is zero if there was none.
+	// SinceTeamActivity is the time since then in whole days.
+	LastTeamActivity  time.Time
+	SinceTeamActivity time.Duration
+
+	// StaleAfter and PendingCIGrace are the repo's settings from the config. For the stale issue templates, StaleAfter is
+	// the repo's stale_issues.stale_after.
+	StaleAfter     time.Duration
+	PendingCIGrace time.Duration
+
+	// Commits are the PR's commits that failed signature verification for unsigned_comment, or the sign-off check for
+	// signoff_comment; empty for other templates
+	Commits []Commit
+	// SensitiveFiles are the changed files matching sensitive_paths; only filled in for the pending CI templates
+	SensitiveFiles []string
+	// ChangedLines is the PR's additions plus deletions, leaving out size_labels.exclude; only filled in for oversize_comment
+	ChangedLines int
+}
+
+// Commit is a single commit that failed signature verification or the sign-off check
+type Commit struct {
+	SHA    string
+	Author string
+	Email  string
+	// Reason is GitHub's verification reason, e.g. unsigned, unknown_key or bad_email, or for the sign-off check
+	// missing_signoff or signoff_mismatch
+	Reason string
+	// Description explains Reason to the commit's author
+	Description string
+}
+
+var funcs = template.FuncMap{
+	"days":  days,
+	"short": ShortSHA,
+	"cell":  EscapeTableCell,
+}
+
+// Render executes the named template text with data
+func Render(name string, text string, data Data) (string, error) {
+	tmpl, err := template.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("error parsing template %s: %w", name, err)
+	}
+	var out bytes.Buffer
+	err = tmpl.Execute(&out, data)
+	if err != nil {
+		return "", fmt.Errorf("error rendering template %s: %w", name, err)
+	}
+	return out.String(), nil
+}
+
+// Validate checks the template parses and renders against the fixture, which catches unknown fields and functions
+func Validate(name string, text string) error {
+	_, err := Render(name, text, Fixture())
+	return err
+}
+
+// Fixture is example data for previewing and validating templates without a real pull request
+func Fixture() Data {
+	now := time.Now()
+	created := now.Add(-12 * 24 * time.Hour)
+	lastActivity := now.Add(-9 * 24 * time.Hour)
+	return Data{
+		Repo:              "my-org/my-repo",
+		Owner:             "my-org",
+		RepoName:          "my-repo",
+		Number:            1234,
+		Title:             "Fix typo in README",
+		Author:            "octocat",
+		URL:               "https://github.com/my-org/my-repo/pull/1234",
+		Team:              "my-org/my-team",
+		CreatedAt:         created,
+		Age:               WholeDays(now.Sub(created)),
+		LastTeamActivity:  lastActivity,
+		SinceTeamActivity: WholeDays(now.Sub(lastActivity)),
+		StaleAfter:        7 * 24 * time.Hour,
+		PendingCIGrace:    2 * time.Hour,
+		Commits: []Commit{
+			{SHA: "0123456789abcdef0123456789abcdef01234567", Author: "The Octocat", Email: "octocat@example.com", Reason: "unsigned", Description: "the commit is not signed"},
+		},
+		SensitiveFiles: []string{".github/workflows/test.yml"},
+		ChangedLines:   1500,
+	}
+}
+
+// WholeDays truncates d to a whole number of days
+func WholeDays(d time.Duration) time.Duration {
+	return d.Truncate(24 * time.Hour)
+}
+
+// days formats a duration as a number of days when it is a whole number of them, e.g. "7 days", and as a Go duration otherwise
+func days(d time.Duration) string {
+	if d%(24*time.Hour) != 0 {
+		return d.String()
+	}
+	n := int(d / (24 * time.Hour))
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+// EscapeTableCell keeps pipes in text from ending a markdown table cell
+func EscapeTableCell(text string) string {
+	return strings.ReplaceAll(text, "|", "\\|")
+}
+
+// ShortSHA abbreviates a commit SHA
+func ShortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,198 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/google/go-github/v60/github"
+
+	"github.com/chia-network/github-bot/internal/database"
+	"github.com/chia-network/github-bot/internal/label"
+)
+
+const commandPrefix = "/bot"
+
+const commandUsage = "Usage: `/bot snooze <duration> [stale|pendingci]`, `/bot suppress [stale|pendingci]`, `/bot unsuppress [stale|pendingci]`, `/bot recheck` or `/bot label community|internal`"
+
+// command is a single /bot line from a PR comment
+type command struct {
+	name string
+	args []string
+}
+
+// parseCommands returns every line of the comment that starts with /bot
+func parseCommands(body string) []command {
+	var commands []command
+	for _, line := range strings.Split(body, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != commandPrefix {
+			continue
+		}
+		if len(fields) == 1 {
+			commands = append(commands, command{name: "help"})
+			continue
+		}
+		commands = append(commands, command{name: strings.ToLower(fields[1]), args: fields[2:]})
+	}
+	return commands
+}
+
+// parseSnoozeDuration accepts anything time.ParseDuration does, plus whole days like 3d
+func parseSnoozeDuration(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("%q is not a valid duration, e.g. 3d or 12h", value)
+	}
+	return duration, nil
+}
+
+// handleCommands runs the /bot commands in a PR comment from an internal team member and acknowledges each one
+func (h *Handler) handleCommands(ctx context.Context, event *github.IssueCommentEvent, commands []command) {
+	comment := event.GetComment()
+	owner, repo := event.GetRepo().GetOwner().GetLogin(), event.GetRepo().GetName()
+	number := event.GetIssue().GetNumber()
+	user := comment.GetUser().GetLogin()
+
+	teamMembers, err := h.snapshot.TeamMembers(ctx)
+	if err != nil {
+		slogs.Logr.Error("Error getting team members", "error", err)
+		return
+	}
+	if !teamMembers[user] {
+		slogs.Logr.Info("Ignoring bot command from user outside the internal team", "repository", event.GetRepo().GetFullName(), "PR", number, "user", user)
+		h.acknowledge(ctx, owner, repo, number, comment.GetID(), "confused", fmt.Sprintf("@%s only members of %s can use bot commands.", user, h.cfg.InternalTeam))
+		return
+	}
+
+	for _, cmd := range commands {
+		slogs.Logr.Info("Running bot command", "repository", event.GetRepo().GetFullName(), "PR", number, "user", user, "command", cmd.name, "args", cmd.args)
+		reply, err := h.runCommand(ctx, event, cmd)
+		if err != nil {
+			slogs.Logr.Error("Bot command failed", "repository", event.GetRepo().GetFullName(), "PR", number, "command", cmd.name, "error", err)
+			h.acknowledge(ctx, owner, repo, number, comment.GetID(), "confused", fmt.Sprintf("`/bot %s` failed: %s", cmd.name, err))
+			continue
+		}
+		h.acknowledge(ctx, owner, repo, number, comment.GetID(), "+1", reply)
+	}
+}
+
+func (h *Handler) runCommand(ctx context.Context, event *github.IssueCommentEvent, cmd command) (string, error) {
+	repoName := event.GetRepo().GetName()
+	number := int64(event.GetIssue().GetNumber())
+
+	switch cmd.name {
+	case "snooze":
+		if len(cmd.args) == 0 {
+			return "", fmt.Errorf("a duration is required. %s", commandUsage)
+		}
+		duration, err := parseSnoozeDuration(cmd.args[0])
+		if err != nil {
+			return "", err
+		}
+		stores, err := h.storesFor(cmd.args[1:])
+		if err != nil {
+			return "", err
+		}
+		until := time.Now().Add(duration)
+		for _, store := range stores {
+			if err := store.SnoozeUntil(repoName, number, until); err != nil {
+				return "", err
+			}
+		}
+		return fmt.Sprintf("Notifications for this PR are snoozed until %s.", until.UTC().Format(time.RFC1123)), nil
+
+	case "suppress", "unsuppress":
+		suppress := cmd.name == "suppress"
+		stores, err := h.storesFor(cmd.args)
+		if err != nil {
+			return "", err
+		}
+		for _, store := range stores {
+			if err := store.UpdateSuppressMessages(repoName, number, suppress); err != nil {
+				return "", err
+			}
+			if !suppress {
+				if err := store.SnoozeUntil(repoName, number, time.Time{}); err != nil {
+					return "", err
+				}
+			}
+		}
+		return "", nil
+
+	case "recheck":
+		pr, err := h.getPullRequest(ctx, event.GetRepo(), event.GetIssue().GetNumber())
+		if err != nil {
+			return "", fmt.Errorf("error fetching pull request: %w", err)
+		}
+		h.evaluate(ctx, pr, checks{label: true, files: true, unsigned: true, pendingCI: true})
+		return "", nil
+
+	case "label":
+		if len(cmd.args) != 1 {
+			return "", fmt.Errorf("expected community or internal. %s", commandUsage)
+		}
+		pr, err := h.getPullRequest(ctx, event.GetRepo(), event.GetIssue().GetNumber())
+		if err != nil {
+			return "", fmt.Errorf("error fetching pull request: %w", err)
+		}
+		switch strings.ToLower(cmd.args[0]) {
+		case "community", "external":
+			return "", label.Set(ctx, h.githubClient, h.cfg, pr, false)
+		case "internal":
+			return "", label.Set(ctx, h.githubClient, h.cfg, pr, true)
+		default:
+			return "", fmt.Errorf("unknown label %q, expected community or internal", cmd.args[0])
+		}
+
+	case "help":
+		return commandUsage, nil
+
+	default:
+		return "", fmt.Errorf("unknown command. %s", commandUsage)
+	}
+}
+
+// storesFor picks the notification tables a command applies to: all of them, or only stale or pendingci
+func (h *Handler) storesFor(args []string) ([]database.Store, error) {
+	if len(args) == 0 || strings.EqualFold(args[0], "all") {
+		if len(h.stores) == 0 {
+			return nil, fmt.Errorf("notification state is not available")
+		}
+		stores := make([]database.Store, 0, len(h.stores))
+		for _, store := range h.stores {
+			stores = append(stores, store)
+		}
+		return stores, nil
+	}
+
+	store, ok := h.stores[strings.ToLower(args[0])]
+	if !ok {
+		return nil, fmt.Errorf("unknown notification %q, expected stale or pendingci", args[0])
+	}
+	return []database.Store{store}, nil
+}
+
+// acknowledge reacts to the command's comment and, when there is something to say, replies on the PR
+func (h *Handler) acknowledge(ctx context.Context, owner, repo string, number int, commentID int64, reaction string, reply string) {
+	_, _, err := h.githubClient.Reactions.CreateIssueCommentReaction(ctx, owner, repo, commentID, reaction)
+	if err != nil {
+		slogs.Logr.Error("Error reacting to bot command", "repository", repo, "PR", number, "error", err)
+	}
+	if reply == "" {
+		return
+	}
+	_, _, err = h.githubClient.Issues.CreateComment(ctx, owner, repo, number, &github.IssueComment{Body: github.String(reply)})
+	if err != nil {
+		slogs.Logr.Error("Error replying to bot command", "repository", repo, "PR", number, "error", err)
+	}
+}
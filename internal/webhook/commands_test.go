@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/chia-network/github-bot/internal/database"
+)
+
+func TestParseCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []command
+	}{
+		{"no commands", "LGTM", nil},
+		{"bare prefix asks for help", "/bot", []command{{name: "help"}}},
+		{"command with arguments", "/bot snooze 3d stale", []command{{name: "snooze", args: []string{"3d", "stale"}}}},
+		{"command name is case insensitive", "/bot ReCheck", []command{{name: "recheck", args: []string{}}}},
+		{"surrounding whitespace", "  /bot   suppress  \r", []command{{name: "suppress", args: []string{}}}},
+		{
+			"one command per line",
+			"Thanks!\n/bot suppress pendingci\nsome text\n/bot label community",
+			[]command{{name: "suppress", args: []string{"pendingci"}}, {name: "label", args: []string{"community"}}},
+		},
+		{"prefix must be its own word", "/bots snooze 1d", nil},
+		{"prefix must start the line", "please run /bot recheck", nil},
+	}
+	for _, test := range tests {
+		if got := parseCommands(test.body); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: parseCommands(%q) = %+v, want %+v", test.name, test.body, got, test.want)
+		}
+	}
+}
+
+func TestParseSnoozeDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "3d", want: 72 * time.Hour},
+		{value: "1d", want: 24 * time.Hour},
+		{value: "12h", want: 12 * time.Hour},
+		{value: "90m", want: 90 * time.Minute},
+		{value: "0d", wantErr: true},
+		{value: "-1d", wantErr: true},
+		{value: "-1h", wantErr: true},
+		{value: "0s", wantErr: true},
+		{value: "soon", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := parseSnoozeDuration(test.value)
+		if (err != nil) != test.wantErr {
+			t.Errorf("parseSnoozeDuration(%q) error = %v, want error %t", test.value, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("parseSnoozeDuration(%q) = %s, want %s", test.value, got, test.want)
+		}
+	}
+}
+
+// namedStore tells stores apart in storesFor results, none of its methods are called
+type namedStore struct {
+	database.Store
+	name string
+}
+
+func TestStoresFor(t *testing.T) {
+	h := &Handler{stores: map[string]database.Store{
+		"stale":     namedStore{name: "stale"},
+		"pendingci": namedStore{name: "pendingci"},
+	}}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "no argument selects all", want: []string{"pendingci", "stale"}},
+		{name: "all", args: []string{"all"}, want: []string{"pendingci", "stale"}},
+		{name: "all is case insensitive", args: []string{"ALL"}, want: []string{"pendingci", "stale"}},
+		{name: "stale", args: []string{"stale"}, want: []string{"stale"}},
+		{name: "pendingci is case insensitive", args: []string{"PendingCI"}, want: []string{"pendingci"}},
+		{name: "unknown store", args: []string{"welcome"}, wantErr: true},
+	}
+	for _, test := range tests {
+		stores, err := h.storesFor(test.args)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: storesFor(%v) error = %v, want error %t", test.name, test.args, err, test.wantErr)
+			continue
+		}
+		var got []string
+		for _, store := range stores {
+			got = append(got, store.(namedStore).name)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: storesFor(%v) = %v, want %v", test.name, test.args, got, test.want)
+		}
+	}
+
+	empty := &Handler{}
+	if _, err := empty.storesFor([]string{"all"}); err == nil {
+		t.Error("expected an error when no notification state is available")
+	}
+}
@@ -0,0 +1,203 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/google/go-github/v60/github"
+
+	github2 "github.com/chia-network/github-bot/internal/github"
+	"github.com/chia-network/github-bot/internal/label"
+)
+
+// checks selects which of the polling jobs' checks are re-run for a pull request
+type checks struct {
+	label     bool
+	files     bool
+	unsigned  bool
+	pendingCI bool
+}
+
+// route returns the work to do for an event, or nil when the event is not relevant
+func (h *Handler) route(event interface{}) func(ctx context.Context) {
+	switch e := event.(type) {
+	case *github.PingEvent:
+		slogs.Logr.Info("Received ping from GitHub", "zen", e.GetZen())
+		return nil
+
+	case *github.PullRequestEvent:
+		c, ok := pullRequestChecks(e.GetAction())
+		if !ok {
+			return nil
+		}
+		return func(ctx context.Context) {
+			h.evaluate(ctx, e.GetPullRequest(), c)
+		}
+
+	case *github.PullRequestReviewEvent:
+		return func(ctx context.Context) {
+			h.evaluate(ctx, e.GetPullRequest(), checks{pendingCI: true})
+		}
+
+	case *github.IssueCommentEvent:
+		if !e.GetIssue().IsPullRequest() || e.GetAction() != "created" {
+			return nil
+		}
+		commands := parseCommands(e.GetComment().GetBody())
+		return func(ctx context.Context) {
+			if len(commands) > 0 && !strings.EqualFold(e.GetComment().GetUser().GetType(), "Bot") {
+				h.handleCommands(ctx, e, commands)
+			}
+
+			pr, err := h.getPullRequest(ctx, e.GetRepo(), e.GetIssue().GetNumber())
+			if err != nil {
+				slogs.Logr.Error("Error fetching pull request for comment", "repository", e.GetRepo().GetFullName(), "PR", e.GetIssue().GetNumber(), "error", err)
+				return
+			}
+			h.evaluate(ctx, pr, checks{pendingCI: true})
+		}
+
+	case *github.WorkflowRunEvent:
+		if e.GetWorkflowRun().GetConclusion() != "action_required" {
+			return nil
+		}
+		return func(ctx context.Context) {
+			prs, err := h.workflowRunPullRequests(ctx, e.GetRepo(), e.GetWorkflowRun())
+			if err != nil {
+				slogs.Logr.Error("Error finding pull requests for workflow run", "repository", e.GetRepo().GetFullName(), "run", e.GetWorkflowRun().GetID(), "error", err)
+				return
+			}
+			for _, pr := range prs {
+				h.evaluate(ctx, pr, checks{pendingCI: true})
+			}
+		}
+
+	case *github.MembershipEvent:
+		team := fmt.Sprintf("%s/%s", e.GetOrg().GetLogin(), e.GetTeam().GetSlug())
+		if !strings.EqualFold(team, h.cfg.InternalTeam) {
+			return nil
+		}
+		return func(ctx context.Context) {
+			slogs.Logr.Info("Internal team membership changed, refreshing team members", "team", team, "user", e.GetMember().GetLogin(), "action", e.GetAction())
+			h.snapshot.InvalidateTeam()
+		}
+	}
+
+	return nil
+}
+
+// pullRequestChecks selects the checks for a pull_request action, and reports false for actions that need none
+func pullRequestChecks(action string) (checks, bool) {
+	switch action {
+	case "opened", "reopened", "ready_for_review":
+		return checks{label: true, files: true, unsigned: true}, true
+	case "synchronize":
+		// New commits from a fork need their workflow runs approved again
+		return checks{files: true, unsigned: true, pendingCI: true}, true
+	case "unlabeled":
+		return checks{label: true}, true
+	}
+	return checks{}, false
+}
+
+// evaluate re-runs the selected checks for a single pull request in one of the configured repositories
+func (h *Handler) evaluate(ctx context.Context, pr *github.PullRequest, c checks) {
+	fullName := pr.GetBase().GetRepo().GetFullName()
+	checkRepo, ok := h.cfg.FindCheckRepo(fullName)
+	if !ok {
+		slogs.Logr.Info("Ignoring event for repository that is not in check_repos", "repository", fullName)
+		return
+	}
+	owner, repo := pr.GetBase().GetRepo().GetOwner().GetLogin(), pr.GetBase().GetRepo().GetName()
+
+	teamMembers, err := h.snapshot.TeamMembers(ctx)
+	if err != nil {
+		slogs.Logr.Error("Error getting team members", "error", err)
+		return
+	}
+
+	if c.label && github2.MatchesPR(h.cfg, teamMembers, pr, checkRepo.MinimumNumber, true) {
+		err = label.PullRequest(ctx, h.githubClient, h.cfg, teamMembers, pr)
+		if err != nil {
+			slogs.Logr.Error("Error labeling pull request", "repository", fullName, "PR", pr.GetNumber(), "error", err)
+		}
+		if h.welcomeStore != nil {
+			err = github2.WelcomePR(ctx, h.githubClient, h.cfg, h.welcomeStore, pr)
+			if err != nil {
+				slogs.Logr.Error("Error welcoming first-time contributor", "repository", fullName, "PR", pr.GetNumber(), "error", err)
+			}
+		}
+	}
+
+	if c.files && github2.MatchesPR(h.cfg, teamMembers, pr, checkRepo.MinimumNumber, false) {
+		err = label.Files(ctx, h.githubClient, h.cfg, teamMembers, pr)
+		if err != nil {
+			slogs.Logr.Error("Error applying path and size labels", "repository", fullName, "PR", pr.GetNumber(), "error", err)
+		}
+	}
+
+	if c.unsigned && github2.MatchesPR(h.cfg, teamMembers, pr, checkRepo.MinimumNumber, false) {
+		err = github2.CheckPRUnsignedCommits(ctx, h.githubClient, h.cfg, owner, repo, pr)
+		if err != nil {
+			slogs.Logr.Error("Error checking unsigned commits", "repository", fullName, "PR", pr.GetNumber(), "error", err)
+		}
+	}
+
+	if c.pendingCI && h.onPendingCI != nil && github2.MatchesPR(h.cfg, teamMembers, pr, checkRepo.MinimumNumber, true) {
+		pendingPR, err := github2.PendingCIForPR(ctx, h.githubClient, h.cfg, h.approvalStore, teamMembers, owner, repo, pr)
+		if err != nil {
+			slogs.Logr.Error("Error checking PR for pending CI", "repository", fullName, "PR", pr.GetNumber(), "error", err)
+			return
+		}
+		if pendingPR != nil {
+			h.onPendingCI(*pendingPR)
+		}
+	}
+}
+
+// getPullRequest fetches the full pull request, since comment payloads only carry the issue
+func (h *Handler) getPullRequest(ctx context.Context, repository *github.Repository, number int) (*github.PullRequest, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	pr, _, err := h.githubClient.PullRequests.Get(reqCtx, repository.GetOwner().GetLogin(), repository.GetName(), number)
+	return pr, err
+}
+
+// workflowRunPullRequests resolves the pull requests a workflow run belongs to.
+// GitHub leaves pull_requests empty for runs triggered from forks, so those are looked up by head branch.
+func (h *Handler) workflowRunPullRequests(ctx context.Context, repository *github.Repository, run *github.WorkflowRun) ([]*github.PullRequest, error) {
+	var prs []*github.PullRequest
+	for _, runPR := range run.PullRequests {
+		pr, err := h.getPullRequest(ctx, repository, runPR.GetNumber())
+		if err != nil {
+			return nil, err
+		}
+		prs = append(prs, pr)
+	}
+	if len(prs) > 0 {
+		return prs, nil
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	opts := &github.PullRequestListOptions{
+		State: "open",
+		Head:  fmt.Sprintf("%s:%s", run.GetHeadRepository().GetOwner().GetLogin(), run.GetHeadBranch()),
+	}
+	prs, _, err := h.githubClient.PullRequests.List(reqCtx, repository.GetOwner().GetLogin(), repository.GetName(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The branch may have moved on since the run was created
+	var matching []*github.PullRequest
+	for _, pr := range prs {
+		if pr.GetHead().GetSHA() == run.GetHeadSHA() {
+			matching = append(matching, pr)
+		}
+	}
+	return matching, nil
+}
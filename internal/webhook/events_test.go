@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v60/github"
+)
+
+func TestPullRequestChecks(t *testing.T) {
+	tests := []struct {
+		action string
+		want   checks
+		ok     bool
+	}{
+		{"opened", checks{label: true, files: true, unsigned: true}, true},
+		{"reopened", checks{label: true, files: true, unsigned: true}, true},
+		{"ready_for_review", checks{label: true, files: true, unsigned: true}, true},
+		{"synchronize", checks{files: true, unsigned: true, pendingCI: true}, true},
+		{"unlabeled", checks{label: true}, true},
+		{"labeled", checks{}, false},
+		{"closed", checks{}, false},
+	}
+	for _, test := range tests {
+		got, ok := pullRequestChecks(test.action)
+		if got != test.want || ok != test.ok {
+			t.Errorf("pullRequestChecks(%q) = %+v, %t, want %+v, %t", test.action, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestRoute(t *testing.T) {
+	pullRequestIssue := &github.Issue{PullRequestLinks: &github.PullRequestLinks{URL: github.String("https://api.github.com/repos/o/r/pulls/1")}}
+	tests := []struct {
+		name  string
+		event interface{}
+		want  bool
+	}{
+		{"ping", &github.PingEvent{}, false},
+		{"pull request opened", &github.PullRequestEvent{Action: github.String("opened")}, true},
+		{"pull request closed", &github.PullRequestEvent{Action: github.String("closed")}, false},
+		{"review", &github.PullRequestReviewEvent{Action: github.String("submitted")}, true},
+		{"comment on a pull request", &github.IssueCommentEvent{Action: github.String("created"), Issue: pullRequestIssue}, true},
+		{"edited comment", &github.IssueCommentEvent{Action: github.String("edited"), Issue: pullRequestIssue}, false},
+		{"comment on an issue", &github.IssueCommentEvent{Action: github.String("created"), Issue: &github.Issue{}}, false},
+		{"workflow run awaiting approval", &github.WorkflowRunEvent{WorkflowRun: &github.WorkflowRun{Conclusion: github.String("action_required")}}, true},
+		{"workflow run succeeded", &github.WorkflowRunEvent{WorkflowRun: &github.WorkflowRun{Conclusion: github.String("success")}}, false},
+		{
+			"internal team membership",
+			&github.MembershipEvent{Org: &github.Organization{Login: github.String("chia-network")}, Team: &github.Team{Slug: github.String("core")}},
+			true,
+		},
+		{
+			"other team membership",
+			&github.MembershipEvent{Org: &github.Organization{Login: github.String("Chia-Network")}, Team: &github.Team{Slug: github.String("docs")}},
+			false,
+		},
+		{"unhandled event", &github.StarEvent{}, false},
+	}
+	h := newTestHandler()
+	for _, test := range tests {
+		if got := h.route(test.event) != nil; got != test.want {
+			t.Errorf("%s: routed %t, want %t", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/chia-network/go-modules/pkg/slogs"
+	"github.com/google/go-github/v60/github"
+
+	"github.com/chia-network/github-bot/internal/config"
+	"github.com/chia-network/github-bot/internal/database"
+	github2 "github.com/chia-network/github-bot/internal/github"
+)
+
+// Handler receives GitHub webhook deliveries and re-evaluates the pull requests they refer to
+type Handler struct {
+	cfg          *config.Config
+	githubClient *github.Client
+	secret       []byte
+
+	// onPendingCI is called for every pull request found waiting for CI approval, or whose runs were just auto-approved
+	onPendingCI func(pr github2.PendingPR)
+
+	// snapshot caches the internal team between deliveries and is shared with reconciliation
+	snapshot *github2.Snapshot
+
+	// stores holds the notification state /bot commands change, keyed by stale or pendingci
+	stores map[string]database.Store
+
+	// welcomeStore records welcomed first-time contributors, and is nil when welcome is disabled
+	welcomeStore database.WelcomeStore
+
+	// approvalStore records auto-approved workflow runs, and is nil when auto_approve is disabled
+	approvalStore database.ApprovalStore
+
+	queue chan func(ctx context.Context)
+}
+
+// NewHandler creates a webhook handler. Deliveries are processed one at a time by Run, so Run must be started before serving.
+func NewHandler(cfg *config.Config, githubClient *github.Client, snapshot *github2.Snapshot, onPendingCI func(pr github2.PendingPR), stores map[string]database.Store, welcomeStore database.WelcomeStore, approvalStore database.ApprovalStore) *Handler {
+	return &Handler{
+		cfg:           cfg,
+		githubClient:  githubClient,
+		secret:        []byte(cfg.WebhookSecret),
+		onPendingCI:   onPendingCI,
+		snapshot:      snapshot,
+		stores:        stores,
+		welcomeStore:  welcomeStore,
+		approvalStore: approvalStore,
+		queue:         make(chan func(ctx context.Context), 100),
+	}
+}
+
+// Run processes queued deliveries until the context is cancelled
+func (h *Handler) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-h.queue:
+			job(ctx)
+		}
+	}
+}
+
+// ServeHTTP verifies the X-Hub-Signature-256 header of a delivery and queues it for processing
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, err := github.ValidatePayload(r, h.secret)
+	if err != nil {
+		slogs.Logr.Warn("Rejecting webhook delivery with invalid signature", "delivery", github.DeliveryID(r), "error", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	eventType := github.WebHookType(r)
+	event, err := github.ParseWebHook(eventType, payload)
+	if err != nil {
+		slogs.Logr.Warn("Unable to parse webhook delivery", "delivery", github.DeliveryID(r), "event", eventType, "error", err)
+		http.Error(w, "unable to parse payload", http.StatusBadRequest)
+		return
+	}
+
+	slogs.Logr.Info("Received webhook delivery", "delivery", github.DeliveryID(r), "event", eventType)
+	job := h.route(event)
+	if job == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	select {
+	case h.queue <- job:
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		// Reconciliation will pick up anything dropped here
+		slogs.Logr.Error("Webhook queue is full, dropping delivery", "delivery", github.DeliveryID(r), "event", eventType)
+		http.Error(w, "queue full", http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,168 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chia-network/github-bot/internal/config"
+)
+
+const testSecret = "webhook-secret"
+
+func newTestHandler() *Handler {
+	return NewHandler(&config.Config{WebhookSecret: testSecret, InternalTeam: "Chia-Network/core"}, nil, nil, nil, nil, nil, nil)
+}
+
+// sign returns the X-Hub-Signature-256 GitHub sends for payload
+func sign(secret string, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func delivery(event string, payload string, signature string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-GitHub-Delivery", "test-delivery")
+	if signature != "" {
+		req.Header.Set("X-Hub-Signature-256", signature)
+	}
+	return req
+}
+
+const synchronizePayload = `{"action": "synchronize", "number": 1, "pull_request": {"number": 1}}`
+
+func TestServeHTTP(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		event     string
+		payload   string
+		signature string
+		want      int
+	}{
+		{
+			name:      "signed relevant event is queued",
+			event:     "pull_request",
+			payload:   synchronizePayload,
+			signature: sign(testSecret, synchronizePayload),
+			want:      http.StatusAccepted,
+		},
+		{
+			name:    "unsigned payload is rejected",
+			event:   "pull_request",
+			payload: synchronizePayload,
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name:      "payload signed with another secret is rejected",
+			event:     "pull_request",
+			payload:   synchronizePayload,
+			signature: sign("wrong-secret", synchronizePayload),
+			want:      http.StatusUnauthorized,
+		},
+		{
+			name:      "tampered payload is rejected",
+			event:     "pull_request",
+			payload:   strings.Replace(synchronizePayload, `"number": 1`, `"number": 2`, 1),
+			signature: sign(testSecret, synchronizePayload),
+			want:      http.StatusUnauthorized,
+		},
+		{
+			name:      "irrelevant event needs no work",
+			event:     "ping",
+			payload:   `{"zen": "Keep it logically awesome."}`,
+			signature: sign(testSecret, `{"zen": "Keep it logically awesome."}`),
+			want:      http.StatusNoContent,
+		},
+		{
+			name:      "malformed payload",
+			event:     "pull_request",
+			payload:   `{"action": `,
+			signature: sign(testSecret, `{"action": `),
+			want:      http.StatusBadRequest,
+		},
+		{
+			name:   "only POST is accepted",
+			method: http.MethodGet,
+			want:   http.StatusMethodNotAllowed,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestHandler()
+			req := delivery(test.event, test.payload, test.signature)
+			if test.method != "" {
+				req.Method = test.method
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != test.want {
+				t.Errorf("got status %d, want %d", rec.Code, test.want)
+			}
+
+			queued := len(h.queue)
+			if wantQueued := test.want == http.StatusAccepted; (queued == 1) != wantQueued {
+				t.Errorf("got %d queued jobs, want queued %t", queued, wantQueued)
+			}
+		})
+	}
+}
+
+func TestServeHTTPQueueFull(t *testing.T) {
+	h := newTestHandler()
+	h.queue = make(chan func(ctx context.Context), 1)
+
+	for i, want := range []int{http.StatusAccepted, http.StatusServiceUnavailable} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, delivery("pull_request", synchronizePayload, sign(testSecret, synchronizePayload)))
+		if rec.Code != want {
+			t.Errorf("delivery %d: got status %d, want %d", i+1, rec.Code, want)
+		}
+	}
+}
+
+func TestRunProcessesQueue(t *testing.T) {
+	h := newTestHandler()
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		h.Run(ctx)
+		close(stopped)
+	}()
+
+	var order []int
+	done := make(chan struct{})
+	for i := 1; i <= 3; i++ {
+		h.queue <- func(ctx context.Context) {
+			order = append(order, i)
+			if i == 3 {
+				close(done)
+			}
+		}
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued jobs were not processed")
+	}
+	if len(order) != 3 || order[0] != 1 || order[1] != 2 || order[2] != 3 {
+		t.Errorf("jobs ran in order %v, want [1 2 3]", order)
+	}
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the context was cancelled")
+	}
+}